@@ -0,0 +1,25 @@
+module github.com/Financial-Times/public-six-degrees
+
+go 1.21
+
+require (
+	github.com/Financial-Times/annotations-rw-neo4j e12b6cbadfa4fc89efd8d531f831efd442705512
+	github.com/Financial-Times/base-ft-rw-app-go 74eab27b0c6d6044fd326183ddd37ea8ac0e236e
+	github.com/Financial-Times/concepts-rw-neo4j 797b029a0c81306e03229877097b11ccb7ac4c96
+	github.com/Financial-Times/content-rw-neo4j 5543bceb064cf123f14ab7477966eb113481cd77
+	github.com/Financial-Times/go-fthealth v0.0.0-20171204124831-1b007e2b37b7
+	github.com/Financial-Times/go-logger febee6537e90971bab6f6fe60b71b4a0562dcab3
+	github.com/Financial-Times/http-handlers-go 2c20324ab8870523a84bdb7dae60872b0b42ed14
+	github.com/Financial-Times/neo-model-utils-go 1a5407658c842183647f4799bb66ecac73af97a8
+	github.com/Financial-Times/neo-utils-go bbb7b5cc0660864223be41a599f6339d41e52fba
+	github.com/Financial-Times/service-status-go 3f5199736a3d7ae52394c63aac36834786825e21
+	github.com/gorilla/mux v1.6.2
+	github.com/jawher/mow.cli v1.0.4
+	github.com/jmcvetta/neoism v0.0.0-00010101000000-000000000000
+	github.com/neo4j/neo4j-go-driver/v5 v5.28.4
+	github.com/rcrowley/go-metrics v0.0.0-20180503174638-e2704e165165
+	github.com/sirupsen/logrus v1.0.5
+	github.com/stretchr/testify v1.2.2
+)
+
+replace github.com/jmcvetta/neoism => github.com/Financial-Times/neoism 0a3ba1ab89c477045fceeb0e99d6abcbe901084c
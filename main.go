@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/rand"
 	"fmt"
 	"net/http"
 	"os"
@@ -15,6 +16,8 @@ import (
 	status "github.com/Financial-Times/service-status-go/httphandlers"
 	"github.com/gorilla/mux"
 	"github.com/jawher/mow.cli"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	metrics "github.com/rcrowley/go-metrics"
 )
 
 func main() {
@@ -34,6 +37,13 @@ func main() {
 		EnvVar: "NEO_URL",
 	})
 
+	neoDriver := app.String(cli.StringOpt{
+		Name:   "neo-driver",
+		Value:  "rest",
+		Desc:   "How to connect to neo4j, either rest for the legacy REST endpoint of neo4j 3, or bolt for neo4j 4.4 and later, with a bolt:// or neo4j:// neo-url carrying any credentials",
+		EnvVar: "NEO_DRIVER",
+	})
+
 	port := app.String(cli.StringOpt{
 		Name:   "port",
 		Value:  "8080",
@@ -48,6 +58,55 @@ func main() {
 		EnvVar: "CACHE_DURATION",
 	})
 
+	cursorSecret := app.String(cli.StringOpt{
+		Name:   "cursor-secret",
+		Value:  "",
+		Desc:   "Secret signing the pagination cursors handed to clients. A random one is used if not given, which only suits a single instance as its cursors do not survive restarts",
+		EnvVar: "CURSOR_SECRET",
+	})
+
+	queryTimeout := app.String(cli.StringOpt{
+		Name:   "query-timeout",
+		Value:  "30s",
+		Desc:   "How long the queries of a request may run before answering 504, 0 to run them for as long as their requests last",
+		EnvVar: "QUERY_TIMEOUT",
+	})
+
+	queryTimeouts := app.String(cli.StringOpt{
+		Name:   "query-timeouts",
+		Value:  "network=1m,communities=1m,centralPeople=1m",
+		Desc:   "Comma separated timeouts of the endpoints whose queries should not run for query-timeout, e.g. network=1m,path=45s",
+		EnvVar: "QUERY_TIMEOUTS",
+	})
+
+	resultCacheTTL := app.String(cli.StringOpt{
+		Name:   "result-cache-ttl",
+		Value:  "5m",
+		Desc:   "How long results are cached for in memory, where requests within it share the windows ending now. 0 to disable the cache",
+		EnvVar: "RESULT_CACHE_TTL",
+	})
+
+	resultCacheSize := app.Int(cli.IntOpt{
+		Name:   "result-cache-size",
+		Value:  1000,
+		Desc:   "How many results are cached in memory, dropping the least recently used ones beyond it",
+		EnvVar: "RESULT_CACHE_SIZE",
+	})
+
+	maxStale := app.String(cli.StringOpt{
+		Name:   "max-stale",
+		Value:  "1h",
+		Desc:   "How old cached results may be to answer requests while neo4j fails, with Warning and X-Stale-Age headers. 0 to answer failures with errors",
+		EnvVar: "MAX_STALE",
+	})
+
+	snapshotMinDays := app.Int(cli.IntOpt{
+		Name:   "snapshot-min-days",
+		Value:  0,
+		Desc:   "How many days long a window must be for its most mentioned concepts and co-mention graph to be summed from the daily snapshots built by precompute, like 30 once the Helm chart builds them. 0 to query every window live",
+		EnvVar: "SNAPSHOT_MIN_DAYS",
+	})
+
 	requestLoggingOn := app.Bool(cli.BoolOpt{
 		Name:   "requestLoggingOn",
 		Value:  true,
@@ -66,15 +125,28 @@ func main() {
 	logger.Infof("Application starting with args %s", os.Args)
 
 	app.Action = func() {
-		runServer(*neoURL, *port, *cacheDuration, *requestLoggingOn)
+		runServer(*neoURL, *neoDriver, *port, *cacheDuration, *cursorSecret, *queryTimeout, *queryTimeouts, *resultCacheTTL, *resultCacheSize, *maxStale, *snapshotMinDays, *requestLoggingOn)
 
 		logger.Infof("%s listening on port: %s, connecting to: %s", *appName, *port, *neoURL)
 	}
 
+	app.Command("precompute", "Build the daily snapshots of mentions and co-mentions that long windows are summed from", func(cmd *cli.Cmd) {
+		days := cmd.Int(cli.IntOpt{
+			Name:   "days",
+			Value:  7,
+			Desc:   "How many days before today to build, replacing their previous snapshots. Build 366 once to cover the longest windows, then the last few days regularly to keep up with annotations changing",
+			EnvVar: "PRECOMPUTE_DAYS",
+		})
+
+		cmd.Action = func() {
+			precompute(*neoURL, *neoDriver, *days)
+		}
+	})
+
 	app.Run(os.Args)
 }
 
-func runServer(neoURL string, port string, cacheDuration string, requestLoggingOn bool) {
+func runServer(neoURL string, neoDriver string, port string, cacheDuration string, cursorSecret string, queryTimeout string, queryTimeouts string, resultCacheTTL string, resultCacheSize int, maxStale string, snapshotMinDays int, requestLoggingOn bool) {
 	var cacheControlHeader string
 
 	if duration, durationErr := time.ParseDuration(cacheDuration); durationErr != nil {
@@ -83,24 +155,39 @@ func runServer(neoURL string, port string, cacheDuration string, requestLoggingO
 		cacheControlHeader = fmt.Sprintf("max-age=%s, public", strconv.FormatFloat(duration.Seconds(), 'f', 0, 64))
 	}
 
-	conf := neoutils.ConnectionConfig{
-		BatchSize:     1024,
-		Transactional: false,
-		HTTPClient: &http.Client{
-			Transport: &http.Transport{
-				MaxIdleConnsPerHost: 100,
-			},
-			Timeout: 1 * time.Minute,
-		},
-		BackgroundConnect: true,
+	timeouts, err := sixdegrees.ParseQueryTimeouts(queryTimeout, queryTimeouts)
+	if err != nil {
+		logger.Fatalf("Failed to parse query timeouts, %v", err)
 	}
-	conn, err := neoutils.Connect(neoURL, &conf)
+
+	// Queries over REST given up on are killed in Neo4j, and the client only times out those too slow for any endpoint
+	clientTimeout := 1 * time.Minute
+	if longest := timeouts.Longest(); longest > clientTimeout {
+		clientTimeout = longest
+	}
+	conn, err := connectNeo(neoURL, neoDriver, false, clientTimeout)
 	if err != nil {
 		logger.Fatalf("Error connecting to neo4j %s", err)
 	}
 
 	driver := sixdegrees.NewCypherDriver(conn)
-	handler := sixdegrees.NewHandler(driver, cacheControlHeader)
+	if snapshotMinDays > 0 {
+		driver = sixdegrees.NewSnapshotDriver(driver, conn, time.Duration(snapshotMinDays)*24*time.Hour)
+	}
+
+	cacheTTL, err := time.ParseDuration(resultCacheTTL)
+	if err != nil {
+		logger.Fatalf("Failed to parse result cache ttl, %v", err)
+	}
+	staleness, err := time.ParseDuration(maxStale)
+	if err != nil {
+		logger.Fatalf("Failed to parse max stale, %v", err)
+	}
+	if cacheTTL > 0 {
+		driver = sixdegrees.NewCachedDriver(driver, cacheTTL, staleness, resultCacheSize, metrics.DefaultRegistry)
+	}
+
+	handler := sixdegrees.NewHandler(driver, cacheControlHeader, getCursorSecret(cursorSecret), timeouts)
 	router := mux.NewRouter()
 	handler.RegisterHandlers(router)
 
@@ -114,7 +201,7 @@ func runServer(neoURL string, port string, cacheDuration string, requestLoggingO
 			SystemCode:  "public-six-degrees-api",
 			Name:        "Public Six Degrees API",
 			Description: "Six Degrees Backend provides mostMentionedPeople and connectedPeople endpoints for Six Degrees Frontend.",
-			Checks:      []fthealth.Check{handler.HealthCheck()},
+			Checks:      []fthealth.Check{handler.HealthCheck(), handler.StaleResultsCheck()},
 		},
 		Timeout: 10 * time.Second,
 	}
@@ -130,3 +217,67 @@ func runServer(neoURL string, port string, cacheDuration string, requestLoggingO
 		logger.Fatalf("Unable to start server: %v", err)
 	}
 }
+
+// connectNeo connects to neo4j over REST or Bolt, where only writers are given write transactions
+func connectNeo(neoURL string, neoDriver string, write bool, clientTimeout time.Duration) (neoutils.NeoConnection, error) {
+	switch neoDriver {
+	case "rest":
+		conf := neoutils.ConnectionConfig{
+			BatchSize:     1024,
+			Transactional: false,
+			HTTPClient: &http.Client{
+				Transport: &http.Transport{
+					MaxIdleConnsPerHost: 100,
+				},
+				Timeout: clientTimeout,
+			},
+			BackgroundConnect: true,
+		}
+		conn, err := neoutils.Connect(neoURL, &conf)
+		if err != nil {
+			return nil, err
+		}
+		return sixdegrees.NewRESTConnection(conn), nil
+	case "bolt":
+		driver, err := sixdegrees.ConnectBolt(neoURL)
+		if err != nil {
+			return nil, err
+		}
+		if write {
+			return sixdegrees.NewBoltConnection(driver, neo4j.AccessModeWrite), nil
+		}
+		return sixdegrees.NewBoltConnection(driver, neo4j.AccessModeRead), nil
+	}
+	return nil, fmt.Errorf("unknown neo4j driver %s, must be rest or bolt", neoDriver)
+}
+
+func precompute(neoURL string, neoDriver string, days int) {
+	conn, err := connectNeo(neoURL, neoDriver, true, 30*time.Minute)
+	if err != nil {
+		logger.Fatalf("Error connecting to neo4j %s", err)
+	}
+
+	if err := sixdegrees.EnsurePeopleSearchIndex(conn); err != nil {
+		logger.Fatalf("Failed to create the people search index, %v", err)
+	}
+
+	snapshotDays := sixdegrees.SnapshotDays(time.Now(), days)
+	logger.Infof("Building the snapshots of %d days", len(snapshotDays))
+	if err := sixdegrees.BuildSnapshots(conn, snapshotDays); err != nil {
+		logger.Fatalf("Failed to build snapshots, %v", err)
+	}
+	logger.Infof("Built the snapshots of %d days", len(snapshotDays))
+}
+
+func getCursorSecret(cursorSecret string) []byte {
+	if cursorSecret != "" {
+		return []byte(cursorSecret)
+	}
+
+	logger.Warn("No cursor secret given, signing pagination cursors with a random one")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		logger.Fatalf("Failed to generate a cursor secret, %v", err)
+	}
+	return secret
+}
@@ -0,0 +1,175 @@
+package sixdegrees
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Financial-Times/neo-utils-go/neoutils"
+	"github.com/jmcvetta/neoism"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// ConnectBolt connects to Neo4j over Bolt, with the credentials given in the URL if any. neo4j:// URLs route reads to
+// the followers of a cluster, while bolt:// ones always go to the given server
+func ConnectBolt(neoURL string) (neo4j.DriverWithContext, error) {
+	target, err := url.Parse(neoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := neo4j.NoAuth()
+	if target.User != nil {
+		password, _ := target.User.Password()
+		auth = neo4j.BasicAuth(target.User.Username(), password, "")
+		target.User = nil
+	}
+
+	// Encryption comes with the +s and +ssc schemes, plain ones connect as they are
+	return neo4j.NewDriverWithContext(target.String(), auth)
+}
+
+// NewBoltDriver answers with the same Cypher as NewCypherDriver, read over Bolt
+func NewBoltDriver(driver neo4j.DriverWithContext) Driver {
+	return NewCypherDriver(NewBoltConnection(driver, neo4j.AccessModeRead))
+}
+
+// BoltConnection runs batches of Cypher over Bolt, each batch in a single transaction of the given access mode, filling
+// in the results of its queries the way the REST connection of neoutils does
+type BoltConnection struct {
+	driver     neo4j.DriverWithContext
+	accessMode neo4j.AccessMode
+}
+
+func NewBoltConnection(driver neo4j.DriverWithContext, accessMode neo4j.AccessMode) neoutils.NeoConnection {
+	return &BoltConnection{
+		driver:     driver,
+		accessMode: accessMode,
+	}
+}
+
+func (bc *BoltConnection) CypherBatch(queries []*neoism.CypherQuery) error {
+	return bc.CypherBatchContext(context.Background(), queries)
+}
+
+// CypherBatchContext aborts the transaction of the batch once its context is done, as the driver drops the connection
+// of a query whose context is done, which Neo4j rolls back. The transaction is also given the time left until the
+// deadline of its context, for Neo4j to abort it then should the connection outlive it
+func (bc *BoltConnection) CypherBatchContext(ctx context.Context, queries []*neoism.CypherQuery) error {
+	configurers := []func(*neo4j.TransactionConfig){}
+	if deadline, ok := ctx.Deadline(); ok {
+		configurers = append(configurers, neo4j.WithTxTimeout(time.Until(deadline)))
+	}
+
+	err := bc.runBatch(ctx, queries, configurers)
+	if ctx.Err() != nil {
+		// Neo4j's own timeout of the transaction is the deadline of its context
+		return ctx.Err()
+	}
+	return err
+}
+
+func (bc *BoltConnection) runBatch(ctx context.Context, queries []*neoism.CypherQuery, configurers []func(*neo4j.TransactionConfig)) error {
+	session := bc.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: bc.accessMode})
+	// Sessions are closed even once the context of their batch is done, to give their connections back
+	defer session.Close(context.Background())
+
+	work := func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		results := make([][]map[string]interface{}, len(queries))
+		for i, query := range queries {
+			rows, err := runQuery(ctx, tx, query)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = rows
+		}
+		return results, nil
+	}
+
+	var results interface{}
+	var err error
+	if bc.accessMode == neo4j.AccessModeRead {
+		results, err = session.ExecuteRead(ctx, work, configurers...)
+	} else {
+		results, err = session.ExecuteWrite(ctx, work, configurers...)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Results are only filled in once the transaction is committed, as retried transactions run their queries again
+	for i, rows := range results.([][]map[string]interface{}) {
+		if err := fillResult(queries[i], rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runQuery(ctx context.Context, tx neo4j.ManagedTransaction, query *neoism.CypherQuery) ([]map[string]interface{}, error) {
+	result, err := tx.Run(ctx, query.Statement, query.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []map[string]interface{}{}
+	for result.Next(ctx) {
+		record := result.Record()
+		row := map[string]interface{}{}
+		for i, key := range record.Keys {
+			row[key] = record.Values[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, result.Err()
+}
+
+// Rows are read into the result of a query through JSON, as neoutils reads them into its json tags
+func fillResult(query *neoism.CypherQuery, rows []map[string]interface{}) error {
+	if query.Result == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(encoded, query.Result); err != nil {
+		return fmt.Errorf("could not read results of %s: %v", strings.TrimSpace(query.Statement), err)
+	}
+	return nil
+}
+
+// Schema changes use the syntax of Neo4j 4.4 and 5, the versions spoken to over Bolt, as 3 is only queried over REST
+func (bc *BoltConnection) EnsureConstraints(constraints map[string]string) error {
+	queries := []*neoism.CypherQuery{}
+	for label, property := range constraints {
+		queries = append(queries, &neoism.CypherQuery{
+			Statement: fmt.Sprintf("CREATE CONSTRAINT IF NOT EXISTS FOR (n:%s) REQUIRE n.%s IS UNIQUE", label, property),
+		})
+	}
+	return bc.ensureSchema(queries)
+}
+
+func (bc *BoltConnection) EnsureIndexes(indexes map[string]string) error {
+	queries := []*neoism.CypherQuery{}
+	for label, property := range indexes {
+		queries = append(queries, &neoism.CypherQuery{
+			Statement: fmt.Sprintf("CREATE INDEX IF NOT EXISTS FOR (n:%s) ON (n.%s)", label, property),
+		})
+	}
+	return bc.ensureSchema(queries)
+}
+
+// Neo4j does not mix schema changes with other writes in one transaction, so each change gets its own
+func (bc *BoltConnection) ensureSchema(queries []*neoism.CypherQuery) error {
+	schema := &BoltConnection{driver: bc.driver, accessMode: neo4j.AccessModeWrite}
+	for _, query := range queries {
+		if err := schema.CypherBatch([]*neoism.CypherQuery{query}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package sixdegrees
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmcvetta/neoism"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoltCypherBatch(t *testing.T) {
+	tx := &dummyTransaction{rows: [][]interface{}{
+		{"12345", "Test Person", int64(7)},
+		{"67890", "Other Person", int64(3)},
+	}}
+	conn := NewBoltConnection(&dummyBoltDriver{tx: tx}, neo4j.AccessModeRead)
+
+	results := []struct {
+		UUID      string `json:"uuid"`
+		PrefLabel string `json:"prefLabel"`
+		Mentions  int    `json:"mentions"`
+	}{}
+	err := conn.CypherBatch([]*neoism.CypherQuery{{
+		Statement:  "MATCH (p:Person{prefUUID:$uuid}) WHERE p.mentions > $minimum RETURN p.prefUUID as uuid, p.prefLabel as prefLabel, p.mentions as mentions",
+		Parameters: neoism.Props{"uuid": "12345", "minimum": 1},
+		Result:     &results,
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "MATCH (p:Person{prefUUID:$uuid}) WHERE p.mentions > $minimum RETURN p.prefUUID as uuid, p.prefLabel as prefLabel, p.mentions as mentions", tx.cypher)
+	assert.Equal(t, map[string]interface{}{"uuid": "12345", "minimum": 1}, tx.params)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Other Person", results[1].PrefLabel)
+	assert.Equal(t, 3, results[1].Mentions)
+}
+
+func TestBoltCypherBatchFailure(t *testing.T) {
+	tx := &dummyTransaction{err: errors.New("TEST failing to READ")}
+	conn := NewBoltConnection(&dummyBoltDriver{tx: tx}, neo4j.AccessModeRead)
+
+	results := []neoThingReadStruct{}
+	err := conn.CypherBatch([]*neoism.CypherQuery{{Statement: "MATCH (p:Person) RETURN p.prefUUID as uuid", Result: &results}})
+	assert.EqualError(t, err, "TEST failing to READ")
+	assert.Empty(t, results, "Results should not be filled in by a failed transaction")
+}
+
+func TestBoltCypherBatchAbortsCancelledQueries(t *testing.T) {
+	tx := &dummyTransaction{blocking: true}
+	conn := NewBoltConnection(&dummyBoltDriver{tx: tx}, neo4j.AccessModeRead).(*BoltConnection)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := conn.CypherBatchContext(ctx, []*neoism.CypherQuery{{Statement: "MATCH (p:Person) RETURN p.prefUUID as uuid"}})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, tx.aborted, "The query should have been given the context of its batch, for the driver to abort it")
+}
+
+type dummyBoltDriver struct {
+	neo4j.DriverWithContext
+	tx *dummyTransaction
+}
+
+func (d *dummyBoltDriver) NewSession(ctx context.Context, config neo4j.SessionConfig) neo4j.SessionWithContext {
+	return &dummySession{tx: d.tx, accessMode: config.AccessMode}
+}
+
+type dummySession struct {
+	neo4j.SessionWithContext
+	tx         *dummyTransaction
+	accessMode neo4j.AccessMode
+}
+
+func (s *dummySession) ExecuteRead(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	if s.accessMode != neo4j.AccessModeRead {
+		return nil, errors.New("read transaction in a write session")
+	}
+	return work(s.tx)
+}
+
+func (s *dummySession) Close(ctx context.Context) error {
+	return nil
+}
+
+type dummyTransaction struct {
+	neo4j.ManagedTransaction
+	rows     [][]interface{}
+	err      error
+	cypher   string
+	params   map[string]interface{}
+	blocking bool
+	aborted  bool
+}
+
+// Blocking transactions run their queries until their context is done, as the driver does
+func (tx *dummyTransaction) Run(ctx context.Context, cypher string, params map[string]interface{}) (neo4j.ResultWithContext, error) {
+	tx.cypher = cypher
+	tx.params = params
+	if tx.blocking {
+		<-ctx.Done()
+		tx.aborted = true
+		return nil, ctx.Err()
+	}
+	if tx.err != nil {
+		return nil, tx.err
+	}
+	return &dummyResult{rows: tx.rows, next: -1}, nil
+}
+
+type dummyResult struct {
+	neo4j.ResultWithContext
+	rows [][]interface{}
+	next int
+}
+
+func (r *dummyResult) Next(ctx context.Context) bool {
+	r.next++
+	return r.next < len(r.rows)
+}
+
+func (r *dummyResult) Err() error {
+	return nil
+}
+
+func (r *dummyResult) Record() *neo4j.Record {
+	values := r.rows[r.next]
+	return &neo4j.Record{Keys: []string{"uuid", "prefLabel", "mentions"}[:len(values)], Values: values}
+}
@@ -0,0 +1,419 @@
+package sixdegrees
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	logger "github.com/Financial-Times/go-logger"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+type cachedResult struct {
+	key       string
+	latestKey string
+	value     interface{}
+	found     bool
+	fetched   time.Time
+}
+
+// cachedCall is a query being run for every caller asking for the same result meanwhile
+type cachedCall struct {
+	done  chan struct{}
+	value interface{}
+	found bool
+	err   error
+	stale bool
+	age   time.Duration
+}
+
+type staleResult struct {
+	served time.Time
+	age    time.Duration
+}
+
+// CachedDriver answers repeated queries from a bounded LRU cache of the results of another driver, for as long as its
+// TTL. Windows are keyed to the TTL, so that the default windows ending now are shared by the requests of a TTL as well,
+// and concurrent callers of a query not yet cached wait for a single run of it. Results past their TTL still answer the
+// queries failing to refresh them, like the last results of windows ending as long before now do once the window ending
+// now has moved on, until they are older than the maximum staleness
+type CachedDriver struct {
+	sync.Mutex
+	driver     Driver
+	ttl        time.Duration
+	maxStale   time.Duration
+	maxEntries int
+	now        func() time.Time
+	registry   metrics.Registry
+	lru        *list.List
+	results    map[string]*list.Element
+	latest     map[string]*list.Element
+	calls      map[string]*cachedCall
+	lastStale  staleResult
+}
+
+func NewCachedDriver(driver Driver, ttl time.Duration, maxStale time.Duration, maxEntries int, registry metrics.Registry) *CachedDriver {
+	return &CachedDriver{
+		driver:     driver,
+		ttl:        ttl,
+		maxStale:   maxStale,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		registry:   registry,
+		lru:        list.New(),
+		results:    map[string]*list.Element{},
+		latest:     map[string]*list.Element{},
+		calls:      map[string]*cachedCall{},
+	}
+}
+
+func (cd *CachedDriver) ConnectedPeople(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]ConnectedPerson, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("ConnectedPeople", uuid, window(fromDateEpoch), window(toDateEpoch), page, minimumConnections, contentLimit, contentSort, filter)
+	}
+	value, _, err := cd.cached(ctx, "ConnectedPeople", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		connectedPeople, err := cd.driver.ConnectedPeople(ctx, uuid, fromDateEpoch, toDateEpoch, page, minimumConnections, contentLimit, contentSort, filter)
+		return connectedPeople, true, err
+	})
+	connectedPeople, _ := value.([]ConnectedPerson)
+	return connectedPeople, err
+}
+
+func (cd *CachedDriver) ConnectedConcepts(ctx context.Context, uuid string, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]ConnectedConcept, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("ConnectedConcepts", uuid, conceptType, window(fromDateEpoch), window(toDateEpoch), page, minimumConnections, contentLimit, contentSort, filter)
+	}
+	value, _, err := cd.cached(ctx, "ConnectedConcepts", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		connectedConcepts, err := cd.driver.ConnectedConcepts(ctx, uuid, conceptType, fromDateEpoch, toDateEpoch, page, minimumConnections, contentLimit, contentSort, filter)
+		return connectedConcepts, true, err
+	})
+	connectedConcepts, _ := value.([]ConnectedConcept)
+	return connectedConcepts, err
+}
+
+func (cd *CachedDriver) MostMentioned(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, filter MentionFilter) ([]MentionedThing, bool, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("MostMentioned", conceptType, window(fromDateEpoch), window(toDateEpoch), page, filter)
+	}
+	value, found, err := cd.cached(ctx, "MostMentioned", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		return cd.driver.MostMentioned(ctx, conceptType, fromDateEpoch, toDateEpoch, page, filter)
+	})
+	things, _ := value.([]MentionedThing)
+	return things, found, err
+}
+
+func (cd *CachedDriver) Trending(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, previousFromDateEpoch int64, previousToDateEpoch int64, limit int, rankBy string) ([]TrendingThing, bool, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("Trending", conceptType, window(fromDateEpoch), window(toDateEpoch), window(previousFromDateEpoch), window(previousToDateEpoch), limit, rankBy)
+	}
+	value, found, err := cd.cached(ctx, "Trending", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		return cd.driver.Trending(ctx, conceptType, fromDateEpoch, toDateEpoch, previousFromDateEpoch, previousToDateEpoch, limit, rankBy)
+	})
+	things, _ := value.([]TrendingThing)
+	return things, found, err
+}
+
+func (cd *CachedDriver) Mentions(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, interval string) ([]MentionsBucket, bool, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("Mentions", uuid, window(fromDateEpoch), window(toDateEpoch), interval)
+	}
+	value, found, err := cd.cached(ctx, "Mentions", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		return cd.driver.Mentions(ctx, uuid, fromDateEpoch, toDateEpoch, interval)
+	})
+	buckets, _ := value.([]MentionsBucket)
+	return buckets, found, err
+}
+
+func (cd *CachedDriver) Connection(ctx context.Context, uuid string, otherUUID string, fromDateEpoch int64, toDateEpoch int64, page PageRequest) (Connection, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("Connection", uuid, otherUUID, window(fromDateEpoch), window(toDateEpoch), page)
+	}
+	value, _, err := cd.cached(ctx, "Connection", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		connection, err := cd.driver.Connection(ctx, uuid, otherUUID, fromDateEpoch, toDateEpoch, page)
+		return connection, true, err
+	})
+	connection, _ := value.(Connection)
+	return connection, err
+}
+
+func (cd *CachedDriver) ShortestPath(ctx context.Context, fromUUID string, toUUID string, fromDateEpoch int64, toDateEpoch int64, maxHops int, contentLimit int) (ConnectionPath, bool, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("ShortestPath", fromUUID, toUUID, window(fromDateEpoch), window(toDateEpoch), maxHops, contentLimit)
+	}
+	value, found, err := cd.cached(ctx, "ShortestPath", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		return cd.driver.ShortestPath(ctx, fromUUID, toUUID, fromDateEpoch, toDateEpoch, maxHops, contentLimit)
+	})
+	path, _ := value.(ConnectionPath)
+	return path, found, err
+}
+
+func (cd *CachedDriver) Network(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, depth int, limit int, minimumConnections int) (Network, bool, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("Network", uuid, window(fromDateEpoch), window(toDateEpoch), depth, limit, minimumConnections)
+	}
+	value, found, err := cd.cached(ctx, "Network", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		return cd.driver.Network(ctx, uuid, fromDateEpoch, toDateEpoch, depth, limit, minimumConnections)
+	})
+	network, _ := value.(Network)
+	return network, found, err
+}
+
+func (cd *CachedDriver) CoMentionGraph(ctx context.Context, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) (CoMentionGraph, bool, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("CoMentionGraph", window(fromDateEpoch), window(toDateEpoch), maxNodes, minimumConnections)
+	}
+	value, found, err := cd.cached(ctx, "CoMentionGraph", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		return cd.driver.CoMentionGraph(ctx, fromDateEpoch, toDateEpoch, maxNodes, minimumConnections)
+	})
+	graph, _ := value.(CoMentionGraph)
+	return graph, found, err
+}
+
+// CentralPeople caches whole rankings, as scoring a co-mention graph costs far more than reading it
+func (cd *CachedDriver) CentralPeople(ctx context.Context, metric string, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) ([]CentralThing, bool, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("CentralPeople", metric, window(fromDateEpoch), window(toDateEpoch), maxNodes, minimumConnections)
+	}
+	value, found, err := cd.cached(ctx, "CentralPeople", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		return cd.driver.CentralPeople(ctx, metric, fromDateEpoch, toDateEpoch, maxNodes, minimumConnections)
+	})
+	ranking, _ := value.([]CentralThing)
+	return ranking, found, err
+}
+
+func (cd *CachedDriver) SearchPeople(ctx context.Context, query string, fromDateEpoch int64, toDateEpoch int64, limit int) ([]MatchedThing, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("SearchPeople", query, window(fromDateEpoch), window(toDateEpoch), limit)
+	}
+	value, _, err := cd.cached(ctx, "SearchPeople", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		matched, err := cd.driver.SearchPeople(ctx, query, fromDateEpoch, toDateEpoch, limit)
+		return matched, true, err
+	})
+	matched, _ := value.([]MatchedThing)
+	return matched, err
+}
+
+func (cd *CachedDriver) CanonicalUUID(ctx context.Context, identifier string) (string, bool, error) {
+	keyOf := func(window func(int64) int64) string {
+		return cd.key("CanonicalUUID", identifier)
+	}
+	value, found, err := cd.cached(ctx, "CanonicalUUID", keyOf, func(ctx context.Context) (interface{}, bool, error) {
+		return cd.driver.CanonicalUUID(ctx, identifier)
+	})
+	canonicalUUID, _ := value.(string)
+	return canonicalUUID, found, err
+}
+
+// CheckConnectivity is never cached, as it checks Neo4j as it is now
+func (cd *CachedDriver) CheckConnectivity(ctx context.Context) error {
+	return cd.driver.CheckConnectivity(ctx)
+}
+
+// The parameters of a query are keyed as JSON, which reads the keys of pages behind their pointers
+func (cd *CachedDriver) key(method string, params ...interface{}) string {
+	encoded, _ := json.Marshal(params)
+	return fmt.Sprintf("%s%s", method, encoded)
+}
+
+// window is the start of the TTL an epoch falls in, at most a day so that the days of explicit windows stay apart. Times
+// before now given relative to it are truncated towards now the same way
+func (cd *CachedDriver) window(epoch int64) int64 {
+	resolution := cd.ttl
+	if resolution > 24*time.Hour {
+		resolution = 24 * time.Hour
+	}
+	seconds := int64(resolution / time.Second)
+	if seconds <= 1 {
+		return epoch
+	}
+	return epoch - epoch%seconds
+}
+
+// cached answers with the cached result of a query, or runs it once for all its callers and caches it unless it failed.
+// Failed queries are answered with the last good result instead while it is not older than the maximum staleness, found
+// by the windows of the query or else by how long before now they are, as the windows ending now move on with the TTL.
+// Callers waiting on a query whose own caller gave up on it run it again, as long as they have not given up as well
+func (cd *CachedDriver) cached(ctx context.Context, method string, keyOf func(window func(int64) int64) string, query func(ctx context.Context) (interface{}, bool, error)) (interface{}, bool, error) {
+	key := keyOf(cd.window)
+	now := cd.now().Unix()
+	latestKey := keyOf(func(epoch int64) int64 {
+		return cd.window(epoch - now)
+	})
+
+	for {
+		cd.Lock()
+		last, fresh := cd.get(key)
+		if last == nil {
+			last = cd.getLatest(latestKey)
+		}
+		if fresh {
+			cd.Unlock()
+			metrics.GetOrRegisterCounter(fmt.Sprintf("sixdegrees.cache.%s.hits", method), cd.registry).Inc(1)
+			return last.value, last.found, nil
+		}
+		call, running := cd.calls[key]
+		if !running {
+			call = &cachedCall{done: make(chan struct{})}
+			cd.calls[key] = call
+		}
+		cd.Unlock()
+
+		if !running {
+			metrics.GetOrRegisterCounter(fmt.Sprintf("sixdegrees.cache.%s.misses", method), cd.registry).Inc(1)
+			call.value, call.found, call.err = query(ctx)
+			cd.Lock()
+			delete(cd.calls, key)
+			if call.err == nil {
+				cd.put(key, latestKey, call.value, call.found)
+			} else if last != nil && servesStale(call.err) {
+				logger.WithError(call.err).WithField("query", method).Warn("answering with a stale result")
+				call.value, call.found, call.err = last.value, last.found, nil
+				call.stale = true
+				call.age = cd.now().Sub(last.fetched)
+				cd.lastStale = staleResult{served: cd.now(), age: call.age}
+			}
+			cd.Unlock()
+			close(call.done)
+			if call.stale {
+				cd.servedStale(ctx, method, call.age)
+			}
+			return call.value, call.found, call.err
+		}
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		if (call.err == context.Canceled || call.err == context.DeadlineExceeded) && ctx.Err() == nil {
+			continue
+		}
+		metrics.GetOrRegisterCounter(fmt.Sprintf("sixdegrees.cache.%s.shared", method), cd.registry).Inc(1)
+		if call.stale {
+			cd.servedStale(ctx, method, call.age)
+		}
+		return call.value, call.found, call.err
+	}
+}
+
+func (cd *CachedDriver) servedStale(ctx context.Context, method string, age time.Duration) {
+	metrics.GetOrRegisterCounter(fmt.Sprintf("sixdegrees.cache.%s.stale", method), cd.registry).Inc(1)
+	recordStale(ctx, age)
+}
+
+// Concepts not found are answers of Neo4j, and clients gone away are answered with nothing
+func servesStale(err error) bool {
+	return err != ErrConceptNotFound && err != context.Canceled
+}
+
+// get finds the last good result of a query, telling whether it is still fresh. Results are kept past their TTL for as
+// long as they may be served stale
+func (cd *CachedDriver) get(key string) (*cachedResult, bool) {
+	element, found := cd.results[key]
+	if !found {
+		return nil, false
+	}
+	result := element.Value.(*cachedResult)
+	age := cd.now().Sub(result.fetched)
+	if age >= cd.ttl && age >= cd.maxStale {
+		cd.remove(element)
+		return nil, false
+	}
+	cd.lru.MoveToFront(element)
+	if age >= cd.ttl {
+		return result, false
+	}
+	return result, true
+}
+
+// getLatest finds the last good result of the windows as long before now as the ones of a failed query, while it may
+// still be served stale
+func (cd *CachedDriver) getLatest(latestKey string) *cachedResult {
+	element, found := cd.latest[latestKey]
+	if !found {
+		return nil
+	}
+	result := element.Value.(*cachedResult)
+	if cd.now().Sub(result.fetched) >= cd.maxStale {
+		return nil
+	}
+	return result
+}
+
+// put caches a result as the most recently used, and the latest of its windows as long before now, evicting the least
+// recently used ones beyond the size of the cache
+func (cd *CachedDriver) put(key string, latestKey string, value interface{}, found bool) {
+	result := &cachedResult{key: key, latestKey: latestKey, value: value, found: found, fetched: cd.now()}
+	if element, cached := cd.results[key]; cached {
+		cd.forgetLatest(element)
+		element.Value = result
+		cd.lru.MoveToFront(element)
+		cd.latest[latestKey] = element
+		return
+	}
+	element := cd.lru.PushFront(result)
+	cd.results[key] = element
+	cd.latest[latestKey] = element
+	for cd.lru.Len() > cd.maxEntries {
+		cd.remove(cd.lru.Back())
+	}
+}
+
+func (cd *CachedDriver) remove(element *list.Element) {
+	cd.forgetLatest(element)
+	cd.lru.Remove(element)
+	delete(cd.results, element.Value.(*cachedResult).key)
+}
+
+// forgetLatest forgets a result as the latest of its windows as long before now, unless a later one has replaced it
+func (cd *CachedDriver) forgetLatest(element *list.Element) {
+	latestKey := element.Value.(*cachedResult).latestKey
+	if cd.latest[latestKey] == element {
+		delete(cd.latest, latestKey)
+	}
+}
+
+// StaleResults tells how old the results answering failed queries may be, and when the last of them was served and how
+// old it was, if any
+func (cd *CachedDriver) StaleResults() (maxStale time.Duration, lastServed time.Time, lastAge time.Duration) {
+	cd.Lock()
+	defer cd.Unlock()
+	return cd.maxStale, cd.lastStale.served, cd.lastStale.age
+}
+
+type staleHeadersKey struct{}
+
+// staleHeaders tells the client of a response of the oldest stale result answering it
+type staleHeaders struct {
+	sync.Mutex
+	w     http.ResponseWriter
+	stale bool
+	age   time.Duration
+}
+
+func withStaleHeaders(ctx context.Context, w http.ResponseWriter) context.Context {
+	return context.WithValue(ctx, staleHeadersKey{}, &staleHeaders{w: w})
+}
+
+// recordStale sets the headers of the response of a context answered with a stale result, before it is written. Results
+// of a request may be recorded concurrently, like the ones of a batch
+func recordStale(ctx context.Context, age time.Duration) {
+	sh, ok := ctx.Value(staleHeadersKey{}).(*staleHeaders)
+	if !ok {
+		return
+	}
+	sh.Lock()
+	defer sh.Unlock()
+	if sh.stale && age <= sh.age {
+		return
+	}
+	sh.stale = true
+	sh.age = age
+	sh.w.Header().Set("Warning", `110 - "Response is Stale"`)
+	sh.w.Header().Set("X-Stale-Age", strconv.FormatInt(int64(age/time.Second), 10))
+}
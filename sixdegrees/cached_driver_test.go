@@ -0,0 +1,307 @@
+package sixdegrees
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingDriver counts the most mentioned queries reaching it, holding them until released when given a channel
+type countingDriver struct {
+	*dummyDriver
+	sync.Mutex
+	calls      int
+	started    chan struct{}
+	release    chan struct{}
+	shouldFail bool
+}
+
+func (cd *countingDriver) MostMentioned(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, filter MentionFilter) ([]MentionedThing, bool, error) {
+	cd.Lock()
+	cd.calls++
+	cd.Unlock()
+
+	if cd.started != nil {
+		cd.started <- struct{}{}
+	}
+	if cd.release != nil {
+		select {
+		case <-cd.release:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+	if cd.shouldFail {
+		return nil, false, errors.New("TEST failing to READ")
+	}
+	return []MentionedThing{{Thing: Thing{ID: "http://api.ft.com/things/12345", PrefLabel: "Test Person"}, Mentions: 3}}, true, nil
+}
+
+func (cd *countingDriver) callCount() int {
+	cd.Lock()
+	defer cd.Unlock()
+	return cd.calls
+}
+
+func counterOf(registry metrics.Registry, name string) int64 {
+	return metrics.GetOrRegisterCounter(name, registry).Count()
+}
+
+func TestCachedDriver(t *testing.T) {
+	assert := assert.New(t)
+	driver := &countingDriver{dummyDriver: &dummyDriver{}}
+	registry := metrics.NewRegistry()
+	cached := NewCachedDriver(driver, 5*time.Minute, 0, 10, registry)
+	now := time.Date(2017, 5, 8, 10, 1, 0, 0, time.UTC)
+	cached.now = func() time.Time { return now }
+	filter := MentionFilter{Weighting: "count", Predicates: []string{"mentions"}}
+
+	things, found, err := cached.MostMentioned(context.Background(), "Person", now.AddDate(0, 0, -7).Unix(), now.Unix(), PageRequest{Limit: 10}, filter)
+	assert.NoError(err)
+	assert.True(found)
+	assert.Len(things, 1)
+
+	// The default window ending now is shared within the TTL
+	later := now.Add(2 * time.Minute)
+	_, _, err = cached.MostMentioned(context.Background(), "Person", later.AddDate(0, 0, -7).Unix(), later.Unix(), PageRequest{Limit: 10}, filter)
+	assert.NoError(err)
+	assert.Equal(1, driver.callCount(), "Queries within the same window should be cached")
+
+	_, _, err = cached.MostMentioned(context.Background(), "Person", now.AddDate(0, 0, -7).Unix(), now.Unix(), PageRequest{Limit: 10, After: &PageKey{Score: 3, UUID: "12345"}}, filter)
+	assert.NoError(err)
+	_, _, err = cached.MostMentioned(context.Background(), "Person", now.AddDate(0, 0, -7).Unix(), now.Unix(), PageRequest{Limit: 10}, MentionFilter{Weighting: "relevance", Predicates: []string{"mentions"}})
+	assert.NoError(err)
+	assert.Equal(3, driver.callCount(), "Queries with other parameters should not be cached together")
+
+	now = now.Add(5 * time.Minute)
+	_, _, err = cached.MostMentioned(context.Background(), "Person", now.AddDate(0, 0, -7).Unix(), now.Unix(), PageRequest{Limit: 10}, filter)
+	assert.NoError(err)
+	assert.Equal(4, driver.callCount(), "Expired results should be queried again")
+
+	assert.Equal(int64(1), counterOf(registry, "sixdegrees.cache.MostMentioned.hits"))
+	assert.Equal(int64(4), counterOf(registry, "sixdegrees.cache.MostMentioned.misses"))
+}
+
+func TestCachedDriverEvictsLeastRecentlyUsed(t *testing.T) {
+	assert := assert.New(t)
+	driver := &countingDriver{dummyDriver: &dummyDriver{}}
+	cached := NewCachedDriver(driver, time.Hour, 0, 2, metrics.NewRegistry())
+
+	mostMentioned := func(conceptType string) {
+		_, _, err := cached.MostMentioned(context.Background(), conceptType, 0, 86400, PageRequest{Limit: 10}, MentionFilter{})
+		assert.NoError(err)
+	}
+	mostMentioned("Person")
+	mostMentioned("Organisation")
+	mostMentioned("Person")
+	mostMentioned("Topic")
+	assert.Equal(3, driver.callCount())
+
+	mostMentioned("Person")
+	assert.Equal(3, driver.callCount(), "The most recently used result should be kept")
+	mostMentioned("Organisation")
+	assert.Equal(4, driver.callCount(), "The least recently used result should be evicted")
+}
+
+func TestCachedDriverCachesCentralPeople(t *testing.T) {
+	assert := assert.New(t)
+	driver := &dummyDriver{}
+	cached := NewCachedDriver(driver, time.Hour, 0, 10, metrics.NewRegistry())
+	day := func(d int) int64 {
+		return time.Date(2017, 5, d, 0, 0, 0, 0, time.UTC).Unix()
+	}
+
+	for _, query := range []struct {
+		metric        string
+		fromDateEpoch int64
+	}{
+		{"pagerank", day(1)},
+		{"pagerank", day(1)},
+		{"degree", day(1)},
+		{"pagerank", day(2)},
+	} {
+		ranking, found, err := cached.CentralPeople(context.Background(), query.metric, query.fromDateEpoch, day(8), 100, 5)
+		assert.NoError(err)
+		assert.True(found)
+		assert.Len(ranking, 2)
+	}
+	assert.Equal(3, driver.coMentionGraphCalls, "Rankings should only be computed once per metric and window")
+}
+
+func TestCachedDriverDoesNotCacheErrors(t *testing.T) {
+	assert := assert.New(t)
+	driver := &countingDriver{dummyDriver: &dummyDriver{}, shouldFail: true}
+	cached := NewCachedDriver(driver, time.Hour, 0, 10, metrics.NewRegistry())
+
+	for i := 0; i < 2; i++ {
+		_, _, err := cached.MostMentioned(context.Background(), "Person", 0, 86400, PageRequest{Limit: 10}, MentionFilter{})
+		assert.EqualError(err, "TEST failing to READ")
+	}
+	assert.Equal(2, driver.callCount())
+}
+
+func TestCachedDriverSharesConcurrentQueries(t *testing.T) {
+	assert := assert.New(t)
+	driver := &countingDriver{dummyDriver: &dummyDriver{}, started: make(chan struct{}, 10), release: make(chan struct{})}
+	registry := metrics.NewRegistry()
+	cached := NewCachedDriver(driver, time.Hour, 0, 10, registry)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			things, found, err := cached.MostMentioned(context.Background(), "Person", 0, 86400, PageRequest{Limit: 10}, MentionFilter{})
+			assert.NoError(err)
+			assert.True(found)
+			assert.Len(things, 1)
+		}()
+	}
+	<-driver.started
+	for counterOf(registry, "sixdegrees.cache.MostMentioned.misses") != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	// Gives the other callers the time to wait on the running query
+	time.Sleep(50 * time.Millisecond)
+	close(driver.release)
+	wg.Wait()
+
+	assert.Equal(1, driver.callCount(), "Concurrent queries should run once")
+	assert.Equal(int64(4), counterOf(registry, "sixdegrees.cache.MostMentioned.shared")+counterOf(registry, "sixdegrees.cache.MostMentioned.hits"))
+}
+
+func TestCachedDriverRerunsQueriesGivenUp(t *testing.T) {
+	assert := assert.New(t)
+	driver := &countingDriver{dummyDriver: &dummyDriver{}, started: make(chan struct{}, 10), release: make(chan struct{})}
+	cached := NewCachedDriver(driver, time.Hour, 0, 10, metrics.NewRegistry())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	given := make(chan error)
+	go func() {
+		_, _, err := cached.MostMentioned(ctx, "Person", 0, 86400, PageRequest{Limit: 10}, MentionFilter{})
+		given <- err
+	}()
+	<-driver.started
+
+	waited := make(chan error)
+	go func() {
+		_, found, err := cached.MostMentioned(context.Background(), "Person", 0, 86400, PageRequest{Limit: 10}, MentionFilter{})
+		assert.True(found)
+		waited <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	assert.Equal(context.Canceled, <-given)
+
+	<-driver.started
+	close(driver.release)
+	assert.NoError(<-waited)
+	assert.Equal(2, driver.callCount(), "A query given up on should be run again for the callers still waiting on it")
+}
+
+func TestCachedDriverServesStaleResults(t *testing.T) {
+	assert := assert.New(t)
+	driver := &countingDriver{dummyDriver: &dummyDriver{}}
+	registry := metrics.NewRegistry()
+	cached := NewCachedDriver(driver, 5*time.Minute, time.Hour, 10, registry)
+	now := time.Date(2017, 5, 8, 10, 0, 0, 0, time.UTC)
+	cached.now = func() time.Time { return now }
+
+	_, _, err := cached.MostMentioned(context.Background(), "Person", 0, 86400, PageRequest{Limit: 10}, MentionFilter{})
+	assert.NoError(err)
+
+	driver.shouldFail = true
+	now = now.Add(10 * time.Minute)
+	rec := httptest.NewRecorder()
+	things, found, err := cached.MostMentioned(withStaleHeaders(context.Background(), rec), "Person", 0, 86400, PageRequest{Limit: 10}, MentionFilter{})
+	assert.NoError(err, "Failed queries should be answered with the last good result")
+	assert.True(found)
+	assert.Len(things, 1)
+	assert.Equal(`110 - "Response is Stale"`, rec.Header().Get("Warning"))
+	assert.Equal("600", rec.Header().Get("X-Stale-Age"))
+	assert.Equal(int64(1), counterOf(registry, "sixdegrees.cache.MostMentioned.stale"))
+
+	maxStale, lastServed, lastAge := cached.StaleResults()
+	assert.Equal(time.Hour, maxStale)
+	assert.Equal(now, lastServed)
+	assert.Equal(10*time.Minute, lastAge)
+
+	now = now.Add(time.Hour)
+	_, _, err = cached.MostMentioned(context.Background(), "Person", 0, 86400, PageRequest{Limit: 10}, MentionFilter{})
+	assert.EqualError(err, "TEST failing to READ", "Results older than the maximum staleness should not be served")
+	assert.Equal(3, driver.callCount())
+}
+
+func TestCachedDriverServesStaleResultsOfWindowsEndingNow(t *testing.T) {
+	assert := assert.New(t)
+	driver := &countingDriver{dummyDriver: &dummyDriver{}}
+	cached := NewCachedDriver(driver, 5*time.Minute, time.Hour, 10, metrics.NewRegistry())
+	now := time.Date(2017, 5, 8, 10, 1, 0, 0, time.UTC)
+	cached.now = func() time.Time { return now }
+	mostMentioned := func(ctx context.Context) ([]MentionedThing, bool, error) {
+		return cached.MostMentioned(ctx, "Person", now.AddDate(0, 0, -7).Unix(), now.Unix(), PageRequest{Limit: 10}, MentionFilter{})
+	}
+
+	_, _, err := mostMentioned(context.Background())
+	assert.NoError(err)
+
+	// The default window ending now has moved on to the next TTL by the time Neo4j fails
+	now = now.Add(10 * time.Minute)
+	driver.shouldFail = true
+	rec := httptest.NewRecorder()
+	things, found, err := mostMentioned(withStaleHeaders(context.Background(), rec))
+	assert.NoError(err, "Failed queries of windows ending now should be answered with the last good result of the window ending now before")
+	assert.True(found)
+	assert.Len(things, 1)
+	assert.Equal("600", rec.Header().Get("X-Stale-Age"))
+	assert.Equal(2, driver.callCount())
+
+	_, _, err = cached.MostMentioned(context.Background(), "Person", now.AddDate(0, 0, -7).Unix(), now.Add(-time.Hour).Unix(), PageRequest{Limit: 10}, MentionFilter{})
+	assert.EqualError(err, "TEST failing to READ", "Windows ending at other times before now should not be answered with it")
+
+	now = now.Add(time.Hour)
+	_, _, err = mostMentioned(context.Background())
+	assert.EqualError(err, "TEST failing to READ", "Results older than the maximum staleness should not be served")
+}
+
+func TestGetStaleResults(t *testing.T) {
+	assert := assert.New(t)
+	driver := &countingDriver{dummyDriver: &dummyDriver{}}
+	cached := NewCachedDriver(driver, time.Nanosecond, time.Hour, 10, metrics.NewRegistry())
+	router := mux.NewRouter()
+	handler := NewHandler(cached, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+	handler.RegisterHandlers(router)
+
+	msg, err := handler.StaleResultsChecker()
+	assert.NoError(err)
+	assert.Equal("Serving results up to 1h0m0s old if neo4j fails, none served lately", msg)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("GET", "/sixdegrees/mostMentionedPeople?fromDate=2017-05-01&toDate=2017-05-08", "application/json", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Empty(rec.Header().Get("Warning"), "Fresh results should not be stale")
+
+	driver.shouldFail = true
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("GET", "/sixdegrees/mostMentionedPeople?fromDate=2017-05-01&toDate=2017-05-08", "application/json", nil))
+	assert.Equal(http.StatusOK, rec.Code, "Stale results should be served while neo4j fails")
+	assert.JSONEq(`[{"id": "http://api.ft.com/things/12345", "prefLabel": "Test Person"}]`, rec.Body.String())
+	assert.Equal(`110 - "Response is Stale"`, rec.Header().Get("Warning"))
+	assert.Equal("0", rec.Header().Get("X-Stale-Age"))
+
+	msg, err = handler.StaleResultsChecker()
+	assert.Error(err)
+	assert.Equal("Serving results up to 1h0m0s old while neo4j fails, the last one 0s old", msg)
+
+	msg, err = NewHandler(&dummyDriver{}, "max-age=360, public", testCursorSecret, QueryTimeouts{}).StaleResultsChecker()
+	assert.NoError(err)
+	assert.Equal("Stale results are not served", msg)
+}
@@ -0,0 +1,183 @@
+package sixdegrees
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+const (
+	pageRankDamping       = 0.85
+	pageRankTolerance     = 1e-9
+	maxPageRankIterations = 100
+)
+
+// The centralities that can be asked for, each scoring every person in a co-mention graph
+var centralityMetrics = map[string]func(neighbours []map[int]int) []float64{
+	"pagerank":    pageRank,
+	"betweenness": betweenness,
+	"degree":      weightedDegree,
+}
+
+func isKnownCentralityMetric(metric string) bool {
+	_, found := centralityMetrics[metric]
+	return found
+}
+
+// centralPeople ranks everybody in the co-mention graph of a window read from the given driver, scoring them in Go
+func centralPeople(ctx context.Context, driver Driver, metric string, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) ([]CentralThing, bool, error) {
+	graph, found, err := driver.CoMentionGraph(ctx, fromDateEpoch, toDateEpoch, maxNodes, minimumConnections)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	return rankByCentrality(graph, metric), true, nil
+}
+
+// rankByCentrality scores everybody in a co-mention graph with the given metric, most central first
+func rankByCentrality(graph CoMentionGraph, metric string) []CentralThing {
+	scores := centralityMetrics[metric](coMentionNeighbours(graph))
+
+	ranking := []CentralThing{}
+	for i, person := range graph.People {
+		ranking = append(ranking, CentralThing{Thing: person, Score: scores[i]})
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Score != ranking[j].Score {
+			return ranking[i].Score > ranking[j].Score
+		}
+		return ranking[i].ID < ranking[j].ID
+	})
+	return ranking
+}
+
+// The weighted adjacency of a co-mention graph, indexed like its people
+func coMentionNeighbours(graph CoMentionGraph) []map[int]int {
+	index := map[string]int{}
+	for i, person := range graph.People {
+		index[person.ID] = i
+	}
+
+	neighbours := make([]map[int]int, len(graph.People))
+	for i := range neighbours {
+		neighbours[i] = map[int]int{}
+	}
+	for _, edge := range graph.Edges {
+		source, sourceFound := index[edge.Source]
+		target, targetFound := index[edge.Target]
+		if !sourceFound || !targetFound || source == target {
+			continue
+		}
+		neighbours[source][target] += edge.Count
+		neighbours[target][source] += edge.Count
+	}
+	return neighbours
+}
+
+// How much content everybody shares with the rest of the graph
+func weightedDegree(neighbours []map[int]int) []float64 {
+	degrees := make([]float64, len(neighbours))
+	for i := range neighbours {
+		for _, weight := range neighbours[i] {
+			degrees[i] += float64(weight)
+		}
+	}
+	return degrees
+}
+
+// pageRank walks the graph along co-mentions in proportion to how often they happen. People without any co-mentions
+// hand their rank to everybody equally, so the scores always add up to 1
+func pageRank(neighbours []map[int]int) []float64 {
+	n := len(neighbours)
+	if n == 0 {
+		return []float64{}
+	}
+	degrees := weightedDegree(neighbours)
+
+	ranks := make([]float64, n)
+	for i := range ranks {
+		ranks[i] = 1 / float64(n)
+	}
+
+	for iteration := 0; iteration < maxPageRankIterations; iteration++ {
+		dangling := 0.0
+		for i := range ranks {
+			if degrees[i] == 0 {
+				dangling += ranks[i]
+			}
+		}
+
+		next := make([]float64, n)
+		for i := range next {
+			next[i] = (1-pageRankDamping)/float64(n) + pageRankDamping*dangling/float64(n)
+		}
+		for i := range neighbours {
+			for neighbour, weight := range neighbours[i] {
+				next[neighbour] += pageRankDamping * ranks[i] * float64(weight) / degrees[i]
+			}
+		}
+
+		change := 0.0
+		for i := range ranks {
+			change += math.Abs(next[i] - ranks[i])
+		}
+		ranks = next
+		if change < pageRankTolerance {
+			break
+		}
+	}
+	return ranks
+}
+
+// betweenness counts, for everybody, the shortest paths between two other people that go through them (Brandes' algorithm).
+// A path is as short as its number of hops, however much content each hop shares
+func betweenness(neighbours []map[int]int) []float64 {
+	n := len(neighbours)
+	scores := make([]float64, n)
+
+	for source := 0; source < n; source++ {
+		stack := []int{}
+		predecessors := make([][]int, n)
+		paths := make([]float64, n)
+		paths[source] = 1
+		distances := make([]int, n)
+		for i := range distances {
+			distances[i] = -1
+		}
+		distances[source] = 0
+
+		queue := []int{source}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			stack = append(stack, current)
+			for neighbour := range neighbours[current] {
+				if distances[neighbour] < 0 {
+					distances[neighbour] = distances[current] + 1
+					queue = append(queue, neighbour)
+				}
+				if distances[neighbour] == distances[current]+1 {
+					paths[neighbour] += paths[current]
+					predecessors[neighbour] = append(predecessors[neighbour], current)
+				}
+			}
+		}
+
+		dependencies := make([]float64, n)
+		for i := len(stack) - 1; i >= 0; i-- {
+			current := stack[i]
+			for _, predecessor := range predecessors[current] {
+				dependencies[predecessor] += paths[predecessor] / paths[current] * (1 + dependencies[current])
+			}
+			if current != source {
+				scores[current] += dependencies[current]
+			}
+		}
+	}
+
+	// Every path was found from both of its ends
+	for i := range scores {
+		scores[i] /= 2
+	}
+	return scores
+}
@@ -0,0 +1,91 @@
+package sixdegrees
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankByCentrality(t *testing.T) {
+	// Two triangles bridged by c and d
+	bridged := CoMentionGraph{
+		People: people("a", "b", "c", "d", "e", "f"),
+		Edges: []NetworkEdge{
+			edge("a", "b", 4), edge("a", "c", 4), edge("b", "c", 4),
+			edge("d", "e", 1), edge("d", "f", 1), edge("e", "f", 1),
+			edge("c", "d", 1),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		graph    CoMentionGraph
+		metric   string
+		expected []CentralThing
+	}{
+		{
+			name:     "EmptyGraph",
+			graph:    CoMentionGraph{People: []Thing{}, Edges: []NetworkEdge{}},
+			metric:   "pagerank",
+			expected: []CentralThing{},
+		},
+		{
+			name:   "Degree",
+			graph:  bridged,
+			metric: "degree",
+			expected: []CentralThing{
+				central("c", 9), central("a", 8), central("b", 8), central("d", 3), central("e", 2), central("f", 2),
+			},
+		},
+		{
+			name:   "Betweenness",
+			graph:  bridged,
+			metric: "betweenness",
+			expected: []CentralThing{
+				central("c", 6), central("d", 6), central("a", 0), central("b", 0), central("e", 0), central("f", 0),
+			},
+		},
+		{
+			name: "BetweennessSharedBetweenEqualPaths",
+			graph: CoMentionGraph{
+				People: people("a", "b", "c", "d"),
+				Edges:  []NetworkEdge{edge("a", "b", 1), edge("a", "c", 1), edge("b", "d", 1), edge("c", "d", 1)},
+			},
+			metric: "betweenness",
+			expected: []CentralThing{
+				central("a", 0.5), central("b", 0.5), central("c", 0.5), central("d", 0.5),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, rankByCentrality(test.graph, test.metric), fmt.Sprintf("%s: Wrong ranking", test.name))
+	}
+}
+
+func TestPageRank(t *testing.T) {
+	graph := CoMentionGraph{
+		People: people("hub", "a", "b", "c", "loner"),
+		Edges:  []NetworkEdge{edge("hub", "a", 3), edge("hub", "b", 1), edge("hub", "c", 1)},
+	}
+
+	ranking := rankByCentrality(graph, "pagerank")
+
+	total := 0.0
+	for _, thing := range ranking {
+		total += thing.Score
+	}
+	assert.InDelta(t, 1.0, total, 1e-6, "PageRank scores should add up to 1")
+
+	order := []string{}
+	for _, thing := range ranking {
+		order = append(order, thing.PrefLabel)
+	}
+	assert.Equal(t, []string{"hub", "a", "b", "c", "loner"}, order, "Wrong PageRank order")
+	assert.Equal(t, ranking[2].Score, ranking[3].Score, "Equally co-mentioned people should rank equally")
+}
+
+func central(label string, score float64) CentralThing {
+	return CentralThing{Thing: Thing{ID: "http://api.ft.com/things/" + label, PrefLabel: label}, Score: score}
+}
@@ -0,0 +1,106 @@
+package sixdegrees
+
+import (
+	"sort"
+)
+
+const maxLabelPropagationRounds = 100
+
+// detectCommunities partitions a co-mention graph with weighted label propagation: everybody repeatedly joins the community
+// they are most strongly co-mentioned with, until nobody moves. People are visited in a fixed order, and ties go to their
+// current community first and then to the lowest one, so the same graph always gives the same communities.
+// People left on their own are not a community
+func detectCommunities(graph CoMentionGraph) []Community {
+	neighbours := coMentionNeighbours(graph)
+
+	labels := make([]int, len(graph.People))
+	for i := range labels {
+		labels[i] = i
+	}
+
+	for round := 0; round < maxLabelPropagationRounds; round++ {
+		moved := false
+		for i := range labels {
+			weights := map[int]int{}
+			maxWeight := 0
+			for neighbour, weight := range neighbours[i] {
+				weights[labels[neighbour]] += weight
+				if weights[labels[neighbour]] > maxWeight {
+					maxWeight = weights[labels[neighbour]]
+				}
+			}
+			if maxWeight == 0 || weights[labels[i]] == maxWeight {
+				continue
+			}
+
+			best := -1
+			for label, weight := range weights {
+				if weight == maxWeight && (best == -1 || label < best) {
+					best = label
+				}
+			}
+			labels[i] = best
+			moved = true
+		}
+		if !moved {
+			break
+		}
+	}
+
+	return toCommunities(graph.People, neighbours, labels)
+}
+
+// Members are ordered by how strongly they are co-mentioned within their community, the most central one naming it
+func toCommunities(people []Thing, neighbours []map[int]int, labels []int) []Community {
+	membersByLabel := map[int][]int{}
+	for i, label := range labels {
+		membersByLabel[label] = append(membersByLabel[label], i)
+	}
+
+	communities := []Community{}
+	for label, members := range membersByLabel {
+		if len(members) < 2 {
+			continue
+		}
+
+		degrees := map[int]int{}
+		internalWeight := 0
+		for _, member := range members {
+			for neighbour, weight := range neighbours[member] {
+				if labels[neighbour] == label {
+					degrees[member] += weight
+					internalWeight += weight
+				}
+			}
+		}
+
+		sort.Slice(members, func(i, j int) bool {
+			if degrees[members[i]] != degrees[members[j]] {
+				return degrees[members[i]] > degrees[members[j]]
+			}
+			return people[members[i]].ID < people[members[j]].ID
+		})
+
+		community := Community{
+			Label:   people[members[0]].PrefLabel,
+			Members: []Thing{},
+			// Every internal edge was counted from both of its ends
+			InternalWeight: internalWeight / 2,
+		}
+		for _, member := range members {
+			community.Members = append(community.Members, people[member])
+		}
+		communities = append(communities, community)
+	}
+
+	sort.Slice(communities, func(i, j int) bool {
+		if communities[i].InternalWeight != communities[j].InternalWeight {
+			return communities[i].InternalWeight > communities[j].InternalWeight
+		}
+		if len(communities[i].Members) != len(communities[j].Members) {
+			return len(communities[i].Members) > len(communities[j].Members)
+		}
+		return communities[i].Members[0].ID < communities[j].Members[0].ID
+	})
+	return communities
+}
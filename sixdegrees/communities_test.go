@@ -0,0 +1,71 @@
+package sixdegrees
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectCommunities(t *testing.T) {
+	tests := []struct {
+		name     string
+		graph    CoMentionGraph
+		expected []Community
+	}{
+		{
+			name:     "EmptyGraph",
+			graph:    CoMentionGraph{People: []Thing{}, Edges: []NetworkEdge{}},
+			expected: []Community{},
+		},
+		{
+			name:     "NobodyConnected",
+			graph:    CoMentionGraph{People: people("a", "b"), Edges: []NetworkEdge{}},
+			expected: []Community{},
+		},
+		{
+			name: "TwoClustersWithAWeakBridge",
+			graph: CoMentionGraph{
+				People: people("a", "b", "c", "d", "e", "f", "g"),
+				Edges: []NetworkEdge{
+					edge("a", "b", 5), edge("a", "c", 4), edge("b", "c", 3),
+					edge("d", "e", 2), edge("d", "f", 2), edge("e", "f", 2),
+					edge("c", "d", 1),
+				},
+			},
+			expected: []Community{
+				{Label: "a", Members: people("a", "b", "c"), InternalWeight: 12},
+				{Label: "d", Members: people("d", "e", "f"), InternalWeight: 6},
+			},
+		},
+		{
+			name: "MostCentralMemberFirst",
+			graph: CoMentionGraph{
+				People: people("a", "b", "c"),
+				Edges: []NetworkEdge{
+					edge("a", "c", 2), edge("b", "c", 3), edge("x", "c", 9),
+				},
+			},
+			expected: []Community{
+				{Label: "c", Members: people("c", "b", "a"), InternalWeight: 5},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, detectCommunities(test.graph), fmt.Sprintf("%s: Wrong communities", test.name))
+		assert.Equal(t, detectCommunities(test.graph), detectCommunities(test.graph), fmt.Sprintf("%s: Communities should be stable", test.name))
+	}
+}
+
+func people(labels ...string) []Thing {
+	things := []Thing{}
+	for _, label := range labels {
+		things = append(things, Thing{ID: "http://api.ft.com/things/" + label, PrefLabel: label})
+	}
+	return things
+}
+
+func edge(source string, target string, count int) NetworkEdge {
+	return NetworkEdge{Source: "http://api.ft.com/things/" + source, Target: "http://api.ft.com/things/" + target, Count: count}
+}
@@ -0,0 +1,202 @@
+package sixdegrees
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	logger "github.com/Financial-Times/go-logger"
+)
+
+const (
+	maxConnectedPeopleBatchSize    = 50
+	maxConnectedPeopleBatchWorkers = 4
+	maxConnectedPeopleBatchBytes   = 1 << 20
+)
+
+// ConnectedPeopleParams are the parameters of connectedPeople, where omitted ones stand for their defaults. Numbers are
+// pointers, as 0 is a number clients can ask for
+type ConnectedPeopleParams struct {
+	FromDate           string `json:"fromDate,omitempty"`
+	ToDate             string `json:"toDate,omitempty"`
+	Limit              *int   `json:"limit,omitempty"`
+	MinimumConnections *int   `json:"minimumConnections,omitempty"`
+	ContentLimit       *int   `json:"contentLimit,omitempty"`
+	ContentSort        string `json:"contentSort,omitempty"`
+}
+
+// ConnectedPeopleBatchItem is one person of a batch, whose own parameters override the shared ones
+type ConnectedPeopleBatchItem struct {
+	UUID string `json:"uuid"`
+	ConnectedPeopleParams
+}
+
+type ConnectedPeopleBatchRequest struct {
+	ConnectedPeopleParams
+	Items []ConnectedPeopleBatchItem `json:"items"`
+}
+
+// ConnectedPeopleBatchResult is either the connected people of a person, or the status and message it failed with
+type ConnectedPeopleBatchResult struct {
+	Status          int               `json:"status"`
+	Message         string            `json:"message,omitempty"`
+	ConnectedPeople []ConnectedPerson `json:"connectedPeople,omitempty"`
+}
+
+// GetConnectedPeopleBatch answers connectedPeople for many people at once, keyed by their UUIDs. People are queried by a
+// bounded pool of workers, so a batch never takes more than a few connections from the Neo4j pool, and each of them
+// fails on its own
+func (hh *Handler) GetConnectedPeopleBatch(w http.ResponseWriter, request *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	batch := ConnectedPeopleBatchRequest{}
+	err := json.NewDecoder(http.MaxBytesReader(w, request.Body, maxConnectedPeopleBatchBytes)).Decode(&batch)
+	if err == nil {
+		err = validateConnectedPeopleBatch(batch)
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not read connected people batch")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting batch body, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	// The whole batch shares the time given to it
+	ctx, cancel := hh.queryContext(w, request, "connectedPeopleBatch")
+	defer cancel()
+
+	items := make(chan ConnectedPeopleBatchItem)
+	results := map[string]ConnectedPeopleBatchResult{}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	workers := maxConnectedPeopleBatchWorkers
+	if len(batch.Items) < workers {
+		workers = len(batch.Items)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				result := hh.connectedPeopleOf(ctx, item.UUID, mergeConnectedPeopleParams(batch.ConnectedPeopleParams, item.ConnectedPeopleParams))
+				lock.Lock()
+				results[item.UUID] = result
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, item := range batch.Items {
+		items <- item
+	}
+	close(items)
+	wg.Wait()
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(results)
+}
+
+func validateConnectedPeopleBatch(batch ConnectedPeopleBatchRequest) error {
+	if len(batch.Items) == 0 || len(batch.Items) > maxConnectedPeopleBatchSize {
+		return fmt.Errorf("items must hold between 1 and %d people", maxConnectedPeopleBatchSize)
+	}
+	if err := validateConnectedPeopleParams(batch.ConnectedPeopleParams); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, item := range batch.Items {
+		if item.UUID == "" {
+			return fmt.Errorf("every item must have a uuid")
+		}
+		if seen[item.UUID] {
+			return fmt.Errorf("uuid %s is repeated", item.UUID)
+		}
+		seen[item.UUID] = true
+		if err := validateConnectedPeopleParams(item.ConnectedPeopleParams); err != nil {
+			return fmt.Errorf("%v for uuid %s", err, item.UUID)
+		}
+	}
+	return nil
+}
+
+// Limits are checked before any worker slices results with them
+func validateConnectedPeopleParams(params ConnectedPeopleParams) error {
+	if params.Limit != nil && (*params.Limit < 1 || *params.Limit > maxResultLimit) {
+		return fmt.Errorf("limit must be between 1 and %d", maxResultLimit)
+	}
+	if params.MinimumConnections != nil && *params.MinimumConnections < 0 {
+		return fmt.Errorf("minimumConnections must not be negative")
+	}
+	if params.ContentLimit != nil && *params.ContentLimit < 0 {
+		return fmt.Errorf("contentLimit must not be negative")
+	}
+	return nil
+}
+
+func mergeConnectedPeopleParams(shared ConnectedPeopleParams, own ConnectedPeopleParams) ConnectedPeopleParams {
+	merged := shared
+	if own.FromDate != "" {
+		merged.FromDate = own.FromDate
+	}
+	if own.ToDate != "" {
+		merged.ToDate = own.ToDate
+	}
+	if own.Limit != nil {
+		merged.Limit = own.Limit
+	}
+	if own.MinimumConnections != nil {
+		merged.MinimumConnections = own.MinimumConnections
+	}
+	if own.ContentLimit != nil {
+		merged.ContentLimit = own.ContentLimit
+	}
+	if own.ContentSort != "" {
+		merged.ContentSort = own.ContentSort
+	}
+	return merged
+}
+
+// connectedPeopleOf answers one person of a batch as /sixdegrees/v2/connectedPeople would, with its default filters
+func (hh *Handler) connectedPeopleOf(ctx context.Context, uuid string, params ConnectedPeopleParams) ConnectedPeopleBatchResult {
+	fromDate, toDate, err := getDateTimePeriod(params.FromDate, params.ToDate)
+	if err != nil {
+		return ConnectedPeopleBatchResult{Status: http.StatusBadRequest, Message: fmt.Sprintf("Error converting toDate or fromDate params: fromDate=%s, toDate=%s", params.FromDate, params.ToDate)}
+	}
+	contentSort, err := getContentSort(params.ContentSort, defaultContentSortV2)
+	if err != nil {
+		return ConnectedPeopleBatchResult{Status: http.StatusBadRequest, Message: fmt.Sprintf("Error converting contentSort param, err=%v", err)}
+	}
+
+	limit := withDefault(params.Limit, defaultConnectedPeopleResultLimit)
+	minimumConnections := withDefault(params.MinimumConnections, defaultMinConnections)
+	contentLimit := withDefault(params.ContentLimit, defaultContentLimit)
+	filter := MentionFilter{Weighting: defaultWeighting, Predicates: []string{defaultPredicate}}
+
+	connectedPeople, err := hh.driver.ConnectedPeople(ctx, uuid, fromDate.Unix(), toDate.Unix(), PageRequest{Limit: limit}, minimumConnections, contentLimit, contentSort, filter)
+	if err == context.DeadlineExceeded {
+		return ConnectedPeopleBatchResult{Status: http.StatusGatewayTimeout, Message: "Timed out retrieving result from DB"}
+	}
+	if err == ErrConceptNotFound {
+		return ConnectedPeopleBatchResult{Status: http.StatusNotFound, Message: fmt.Sprintf("No person found with uuid %s", uuid)}
+	}
+	if err != nil {
+		logger.WithError(err).WithField("uuid", uuid).Error("could not retrieve connected people")
+		return ConnectedPeopleBatchResult{Status: http.StatusInternalServerError, Message: fmt.Sprintf("Error retrieving result for %s, err=%v", uuid, err)}
+	}
+
+	// Batches have no cursors, so the extra result telling of a next page is dropped
+	if len(connectedPeople) > limit {
+		connectedPeople = connectedPeople[:limit]
+	}
+	return ConnectedPeopleBatchResult{Status: http.StatusOK, ConnectedPeople: connectedPeople}
+}
+
+func withDefault(value *int, defaultValue int) int {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
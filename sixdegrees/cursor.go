@@ -0,0 +1,105 @@
+package sixdegrees
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// cursorQuery is everything a page of results depends on besides its size, so that following pages keep to the same
+// window and filters even when the defaults have moved on
+type cursorQuery struct {
+	Path               string        `json:"path"`
+	UUID               string        `json:"uuid,omitempty"`
+	OtherUUID          string        `json:"otherUuid,omitempty"`
+	ConceptType        string        `json:"type,omitempty"`
+	FromDateEpoch      int64         `json:"fromDate"`
+	ToDateEpoch        int64         `json:"toDate"`
+	MinimumConnections int           `json:"minimumConnections,omitempty"`
+	ContentLimit       int           `json:"contentLimit,omitempty"`
+	ContentSort        string        `json:"contentSort,omitempty"`
+	Filter             MentionFilter `json:"filter"`
+	Scored             bool          `json:"scored,omitempty"`
+	Include            []string      `json:"include,omitempty"`
+}
+
+type cursor struct {
+	Query cursorQuery `json:"query"`
+	After PageKey     `json:"after"`
+}
+
+// cursorSigner makes cursors opaque to clients, and tamper-proof, by signing them with a secret
+type cursorSigner struct {
+	secret []byte
+}
+
+func (cs cursorSigner) sign(c cursor) string {
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(cs.mac(payload))
+}
+
+func (cs cursorSigner) verify(token string) (cursor, error) {
+	c := cursor{}
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return c, errors.New("malformed cursor")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return c, errors.New("malformed cursor")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, cs.mac(payload)) {
+		return c, errors.New("invalid cursor signature")
+	}
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return c, errors.New("malformed cursor")
+	}
+	return c, nil
+}
+
+func (cs cursorSigner) mac(payload []byte) []byte {
+	mac := hmac.New(sha256.New, cs.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// getPage resolves the page asked for: the first one, or the one after a cursor, whose query then replaces the given one
+func (hh *Handler) getPage(cursorParam string, limit int, query *cursorQuery) (PageRequest, error) {
+	page := PageRequest{Limit: limit}
+	if cursorParam == "" {
+		return page, nil
+	}
+
+	c, err := hh.cursors.verify(cursorParam)
+	if err != nil {
+		return page, err
+	}
+	if c.Query.Path != query.Path {
+		return page, fmt.Errorf("cursor is for %s", c.Query.Path)
+	}
+
+	*query = c.Query
+	page.After = &c.After
+	return page, nil
+}
+
+// setNextLink points to the page following the given last result, keeping the size of the current page
+func (hh *Handler) setNextLink(w http.ResponseWriter, query cursorQuery, page PageRequest, last PageKey) {
+	params := url.Values{}
+	params.Set("cursor", hh.cursors.sign(cursor{Query: query, After: last}))
+	params.Set("limit", strconv.Itoa(page.Limit))
+	w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, query.Path, params.Encode()))
+}
+
+// The UUID at the end of an ID URL
+func uuidOf(id string) string {
+	return id[strings.LastIndex(id, "/")+1:]
+}
@@ -1,13 +1,96 @@
 package sixdegrees
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/Financial-Times/neo-utils-go/neoutils"
+	"github.com/jmcvetta/neoism"
 )
 
+// Labels are interpolated into Cypher statements, so only these are ever allowed
+var conceptTypes = []string{
+	"Person",
+	"Organisation",
+	"Topic",
+	"Location",
+	"Brand",
+	"Genre",
+}
+
+// Weightings are interpolated into Cypher statements as the score property of a mention, none meaning every mention counts as 1
+var mentionWeightings = map[string]string{
+	"count":      "",
+	"relevance":  "relevanceScore",
+	"confidence": "confidenceScore",
+}
+
+// Predicates are interpolated into Cypher statements as the relationship types of annotations, so only these are ever allowed
+var annotationPredicates = map[string]string{
+	"mentions":                "MENTIONS",
+	"majorMentions":           "MAJOR_MENTIONS",
+	"about":                   "ABOUT",
+	"isClassifiedBy":          "IS_CLASSIFIED_BY",
+	"isPrimarilyClassifiedBy": "IS_PRIMARILY_CLASSIFIED_BY",
+	"hasAuthor":               "HAS_AUTHOR",
+	"hasDisplayTag":           "HAS_DISPLAY_TAG",
+}
+
+// Content sorts are interpolated into Cypher statements as the order of the content of a connection, none keeping to UUIDs.
+// Relevance multiplies the relevance scores of both mentions making a co-mention, unscored ones counting as 1
+var contentSorts = map[string]string{
+	"":          "c.uuid ASC",
+	"newest":    "c.publishedDateEpoch DESC, c.uuid ASC",
+	"oldest":    "c.publishedDateEpoch ASC, c.uuid ASC",
+	"relevance": "relevance DESC, c.publishedDateEpoch DESC, c.uuid ASC",
+}
+
+var contentSortNames = []string{"newest", "oldest", "relevance"}
+
+// MentionFilter selects which annotations count as mentions, and how much each of them weighs.
+// Brands and content types restrict the content considered, when given
+type MentionFilter struct {
+	Predicates    []string
+	Weighting     string
+	MinConfidence float64
+	Brands        []string
+	ContentTypes  []string
+}
+
+// PageKey is the sort key of the last result of a page: its score, its count of co-mentions or mentions, and its UUID
+type PageKey struct {
+	Score float64 `json:"score"`
+	Count int     `json:"count"`
+	UUID  string  `json:"uuid"`
+}
+
+// PageRequest asks for the results sorting after a key, or for the first ones when there is none.
+// Drivers return one result more than the limit when there is a next page, for the caller to tell
+type PageRequest struct {
+	Limit int
+	After *PageKey
+}
+
+// ErrConceptNotFound is returned for a concept that is not known at all, unlike one known without any result in a period
+var ErrConceptNotFound = errors.New("concept not found")
+
 type Driver interface {
-	ConnectedPeople(uuid string, fromDateEpoch int64, toDateEpoch int64, limit int, minimumConnections int, contentLimit int) ([]ConnectedPerson, bool, error)
-	MostMentioned(fromDateEpoch int64, toDateEpoch int64, limit int) ([]Thing, bool, error)
-	CheckConnectivity() error
+	ConnectedPeople(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]ConnectedPerson, error)
+	ConnectedConcepts(ctx context.Context, uuid string, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]ConnectedConcept, error)
+	MostMentioned(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, filter MentionFilter) ([]MentionedThing, bool, error)
+	Trending(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, previousFromDateEpoch int64, previousToDateEpoch int64, limit int, rankBy string) ([]TrendingThing, bool, error)
+	Mentions(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, interval string) ([]MentionsBucket, bool, error)
+	Connection(ctx context.Context, uuid string, otherUUID string, fromDateEpoch int64, toDateEpoch int64, page PageRequest) (Connection, error)
+	ShortestPath(ctx context.Context, fromUUID string, toUUID string, fromDateEpoch int64, toDateEpoch int64, maxHops int, contentLimit int) (ConnectionPath, bool, error)
+	Network(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, depth int, limit int, minimumConnections int) (Network, bool, error)
+	CoMentionGraph(ctx context.Context, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) (CoMentionGraph, bool, error)
+	CentralPeople(ctx context.Context, metric string, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) ([]CentralThing, bool, error)
+	SearchPeople(ctx context.Context, query string, fromDateEpoch int64, toDateEpoch int64, limit int) ([]MatchedThing, error)
+	CanonicalUUID(ctx context.Context, identifier string) (string, bool, error)
+	CheckConnectivity(ctx context.Context) error
 }
 
 func NewCypherDriver(conn neoutils.NeoConnection) Driver {
@@ -20,12 +103,182 @@ type CypherDriver struct {
 	conn neoutils.NeoConnection
 }
 
-func (cd CypherDriver) CheckConnectivity() error {
-	return neoutils.Check(cd.conn)
+func (cd CypherDriver) CheckConnectivity(ctx context.Context) error {
+	return untilDone(ctx, func() error {
+		return neoutils.Check(cd.conn)
+	})
+}
+
+// contextConnection is a connection able to abort its queries once their context is done
+type contextConnection interface {
+	CypherBatchContext(ctx context.Context, queries []*neoism.CypherQuery) error
+}
+
+// cypherBatch runs queries until their context is done, when connections able to do so abort them. Others are left to
+// finish them in the background, bounded by the timeout of their own HTTP client
+func (cd CypherDriver) cypherBatch(ctx context.Context, queries []*neoism.CypherQuery) error {
+	if conn, ok := cd.conn.(contextConnection); ok {
+		return conn.CypherBatchContext(ctx, queries)
+	}
+	return untilDone(ctx, func() error {
+		return cd.conn.CypherBatch(queries)
+	})
+}
+
+// untilDone answers with the error of the context instead, once it is done before the given call returns
+func untilDone(ctx context.Context, call func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 type neoMentionsReadStruct struct {
-	UUID      string `json:"uuid"`
-	PrefLabel string `json:"prefLabel"`
-	Mentions  int    `json:"mentions"`
+	UUID         string  `json:"uuid"`
+	PrefLabel    string  `json:"prefLabel"`
+	Mentions     int     `json:"mentions"`
+	ContentCount int     `json:"contentCount"`
+	Score        float64 `json:"score"`
+}
+
+func validateConceptTypes(conceptTypes ...string) error {
+	for _, conceptType := range conceptTypes {
+		if !isKnownConceptType(conceptType) {
+			return fmt.Errorf("unknown concept type %s", conceptType)
+		}
+	}
+	return nil
+}
+
+func isKnownConceptType(conceptType string) bool {
+	for _, known := range conceptTypes {
+		if known == conceptType {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownContentSort(contentSort string) bool {
+	for _, known := range contentSortNames {
+		if known == contentSort {
+			return true
+		}
+	}
+	return false
+}
+
+func validateContentSort(contentSort string) error {
+	if _, ok := contentSorts[contentSort]; !ok {
+		return fmt.Errorf("unknown content sort %s", contentSort)
+	}
+	return nil
+}
+
+func validateMentionFilter(filter MentionFilter) error {
+	if _, ok := mentionWeightings[filter.Weighting]; !ok {
+		return fmt.Errorf("unknown weighting %s", filter.Weighting)
+	}
+	return validatePredicates(filter.Predicates)
+}
+
+func validatePredicates(predicates []string) error {
+	if len(predicates) == 0 {
+		return fmt.Errorf("at least one predicate is required")
+	}
+	for _, predicate := range predicates {
+		if _, ok := annotationPredicates[predicate]; !ok {
+			return fmt.Errorf("unknown predicate %s, must be one of %s", predicate, strings.Join(knownPredicates(), ", "))
+		}
+	}
+	return nil
+}
+
+func knownPredicates() []string {
+	predicates := []string{}
+	for predicate := range annotationPredicates {
+		predicates = append(predicates, predicate)
+	}
+	sort.Strings(predicates)
+	return predicates
+}
+
+// The predicates of the given relationship types, once each and sorted
+func predicatesOf(relationships []string) []string {
+	found := map[string]bool{}
+	for predicate, relationship := range annotationPredicates {
+		for _, r := range relationships {
+			if r == relationship {
+				found[predicate] = true
+			}
+		}
+	}
+
+	predicates := []string{}
+	for predicate := range found {
+		predicates = append(predicates, predicate)
+	}
+	sort.Strings(predicates)
+	return predicates
+}
+
+// The relationship types of the predicates of a filter, as a Cypher alternation like MENTIONS|ABOUT
+func mentionRelationships(filter MentionFilter) string {
+	relationships := []string{}
+	for _, predicate := range filter.Predicates {
+		relationships = append(relationships, annotationPredicates[predicate])
+	}
+	return strings.Join(relationships, "|")
+}
+
+// Annotations without scores, like curated ones, are as relevant and confident as can be
+func mentionWeight(relationship string, filter MentionFilter) string {
+	if property := mentionWeightings[filter.Weighting]; property != "" {
+		return fmt.Sprintf("coalesce(%s.%s, 1.0)", relationship, property)
+	}
+	return "1.0"
+}
+
+// Content is kept when classified by any of the brands of a filter, and labelled with any of its content types
+func contentFilter(content string, filter MentionFilter) string {
+	clauses := ""
+	if len(filter.Brands) > 0 {
+		clauses += fmt.Sprintf(" AND ANY(brand IN [(%s)-[:IS_CLASSIFIED_BY]->(b:Thing) | b.uuid] WHERE brand IN $brands)", content)
+	}
+	if len(filter.ContentTypes) > 0 {
+		clauses += fmt.Sprintf(" AND ANY(label IN labels(%s) WHERE label IN $contentTypes)", content)
+	}
+	return clauses
+}
+
+// Keyset pagination of results ordered by score DESC, count DESC, uuid ASC, which stays stable however deep the page
+func pageFilter(count string, page PageRequest) string {
+	if page.After == nil {
+		return ""
+	}
+	return fmt.Sprintf(`
+		WHERE
+			score < $afterScore
+			OR (score = $afterScore AND %[1]s < $afterCount)
+			OR (score = $afterScore AND %[1]s = $afterCount AND uuid > $afterUUID)`, count)
+}
+
+func pageParameters(params neoism.Props, page PageRequest) neoism.Props {
+	// One more result than asked for tells whether there is a next page
+	params["limit"] = page.Limit + 1
+	if page.After != nil {
+		params["afterScore"] = page.After.Score
+		params["afterCount"] = page.After.Count
+		params["afterUUID"] = page.After.UUID
+	}
+	return params
 }
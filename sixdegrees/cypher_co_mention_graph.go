@@ -0,0 +1,59 @@
+package sixdegrees
+
+import (
+	"context"
+	"github.com/jmcvetta/neoism"
+)
+
+// CoMentionGraph is bounded to the most mentioned people of the period, so it stays small enough to be analysed in memory
+func (cd CypherDriver) CoMentionGraph(ctx context.Context, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) (CoMentionGraph, bool, error) {
+	people := []neoThingReadStruct{}
+	peopleQuery := &neoism.CypherQuery{
+		Statement: `
+			MATCH (c:Content)
+			WHERE
+				c.publishedDateEpoch < $toDate
+				AND c.publishedDateEpoch > $fromDate
+			MATCH (c)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p:Person)
+			WITH
+				p,
+				count(distinct(c)) as mentions
+			RETURN
+				p.prefUUID as uuid,
+				p.prefLabel as prefLabel
+			ORDER BY
+				mentions DESC,
+				uuid ASC
+			LIMIT $maxNodes
+		`,
+		Parameters: neoism.Props{
+			"fromDate": fromDateEpoch,
+			"toDate":   toDateEpoch,
+			"maxNodes": maxNodes,
+		},
+		Result: &people,
+	}
+
+	if err := cd.cypherBatch(ctx, []*neoism.CypherQuery{peopleQuery}); err != nil || len(people) == 0 {
+		return CoMentionGraph{}, false, err
+	}
+
+	graph := CoMentionGraph{People: []Thing{}}
+	uuids := []string{}
+	for _, neoPerson := range people {
+		graph.People = append(graph.People, transformToPerson(neoPerson))
+		uuids = append(uuids, neoPerson.UUID)
+	}
+
+	edges := []neoNetworkEdgeReadStruct{}
+	if err := cd.cypherBatch(ctx, []*neoism.CypherQuery{coMentionEdgesQuery(uuids, fromDateEpoch, toDateEpoch, minimumConnections, &edges)}); err != nil {
+		return CoMentionGraph{}, false, err
+	}
+
+	graph.Edges = transformToNetworkEdges(edges)
+	return graph, true, nil
+}
+
+func (cd CypherDriver) CentralPeople(ctx context.Context, metric string, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) ([]CentralThing, bool, error) {
+	return centralPeople(ctx, cd, metric, fromDateEpoch, toDateEpoch, maxNodes, minimumConnections)
+}
@@ -1,81 +1,171 @@
 package sixdegrees
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/Financial-Times/neo-model-utils-go/mapper"
 	"github.com/jmcvetta/neoism"
 )
 
 type neoContentReadStruct struct {
-	UUID      string `json:"uuid"`
-	PrefLabel string `json:"prefLabel"`
+	UUID               string               `json:"uuid"`
+	PrefLabel          string               `json:"prefLabel"`
+	PublishedDateEpoch int64                `json:"publishedDateEpoch"`
+	Labels             []string             `json:"labels"`
+	Brands             []neoThingReadStruct `json:"brands"`
+	Relationships      []string             `json:"relationships"`
 }
 
 type neoConnectedPeopleReadStruct struct {
 	UUID        string                 `json:"uuid"`
 	PrefLabel   string                 `json:"prefLabel"`
 	Count       int                    `json:"count"`
+	Score       float64                `json:"score"`
 	ContentList []neoContentReadStruct `json:"contentList"`
 }
 
-func (cd CypherDriver) ConnectedPeople(uuid string, fromDateEpoch int64, toDateEpoch int64, resultLimit int, minimumConnections int, contentLimit int) ([]ConnectedPerson, bool, error) {
+func (cd CypherDriver) ConnectedPeople(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]ConnectedPerson, error) {
+	results, err := cd.connectedConcepts(ctx, uuid, "Person", "Person", fromDateEpoch, toDateEpoch, page, minimumConnections, contentLimit, contentSort, filter)
+	if err != nil {
+		return []ConnectedPerson{}, err
+	}
+
+	return transformToConnectedPeople(&results), nil
+}
+
+func (cd CypherDriver) ConnectedConcepts(ctx context.Context, uuid string, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]ConnectedConcept, error) {
+	results, err := cd.connectedConcepts(ctx, uuid, "Concept", conceptType, fromDateEpoch, toDateEpoch, page, minimumConnections, contentLimit, contentSort, filter)
+	if err != nil {
+		return []ConnectedConcept{}, err
+	}
+
+	return transformToConnectedConcepts(&results, conceptType), nil
+}
+
+// A concept without connections is only told apart from an unknown one when there are none, to keep the usual case to one query
+func (cd CypherDriver) connectedConcepts(ctx context.Context, uuid string, sourceType string, targetType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]neoConnectedPeopleReadStruct, error) {
+	results, err := cd.queryConnectedConcepts(ctx, uuid, sourceType, targetType, fromDateEpoch, toDateEpoch, page, minimumConnections, contentLimit, contentSort, filter)
+	if err != nil || len(results) > 0 {
+		return results, err
+	}
+
+	exists, err := cd.conceptExists(ctx, uuid, sourceType)
+	if err == nil && !exists {
+		err = ErrConceptNotFound
+	}
+	return results, err
+}
+
+func (cd CypherDriver) conceptExists(ctx context.Context, uuid string, conceptType string) (bool, error) {
+	results := []neoThingReadStruct{}
+	query := &neoism.CypherQuery{
+		Statement: fmt.Sprintf(`
+			MATCH (p:%s{prefUUID:$uuid})
+			RETURN p.prefUUID as uuid
+			LIMIT 1
+		`, conceptType),
+		Parameters: neoism.Props{
+			"uuid": uuid,
+		},
+		Result: &results,
+	}
+
+	err := cd.cypherBatch(ctx, []*neoism.CypherQuery{query})
+	return len(results) > 0, err
+}
+
+func (cd CypherDriver) queryConnectedConcepts(ctx context.Context, uuid string, sourceType string, targetType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]neoConnectedPeopleReadStruct, error) {
 	results := []neoConnectedPeopleReadStruct{}
 
-	statement := `
+	if err := validateConceptTypes(targetType); err != nil {
+		return results, err
+	}
+	if err := validateMentionFilter(filter); err != nil {
+		return results, err
+	}
+	if err := validateContentSort(contentSort); err != nil {
+		return results, err
+	}
+
+	// Connected people have always included the person themselves, counted as co-mentioned by all of their content,
+	// which v1 keeps answering. Connected concepts leave the concept out
+	self := ""
+	if sourceType != "Person" {
+		self = "p2 <> p\n\t\t\tAND "
+	}
+
+	// A co-mention weighs as much as both of its mentions together, and the score of a connection adds them all up
+	statement := fmt.Sprintf(`
 		MATCH (c:Content)
 		WHERE
-			c.publishedDateEpoch < {toDate}
-			AND c.publishedDateEpoch > {fromDate}
-		MATCH (p:Person{prefUUID:{uuid}})<-[:EQUIVALENT_TO]-(:Person)<-[:MENTIONS]-(c)
-		MATCH (c)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p2:Person)
+			c.publishedDateEpoch < $toDate
+			AND c.publishedDateEpoch > $fromDate%[6]s
+		MATCH (p:%[1]s{prefUUID:$uuid})<-[:EQUIVALENT_TO]-(:%[1]s)<-[a:%[5]s]-(c)
+		WHERE coalesce(a.confidenceScore, 1.0) >= $minConfidence
+		MATCH (c)-[a2:%[5]s]->(:%[2]s)-[:EQUIVALENT_TO]->(p2:%[2]s)
+		WHERE
+			%[9]scoalesce(a2.confidenceScore, 1.0) >= $minConfidence
 		WITH
 			c,
 			p,
-			p2
+			p2,
+			max(%[3]s * %[4]s) as weight,
+			max(coalesce(a.relevanceScore, 1.0) * coalesce(a2.relevanceScore, 1.0)) as relevance,
+			collect(distinct(type(a))) + collect(distinct(type(a2))) as relationships
 		ORDER BY
-			c.uuid ASC
+			%[8]s
 		WITH
 			p,
 			count(distinct(c)) as cm,
+			sum(weight) as score,
 			p2,
 			collect({
 				uuid: c.uuid,
-				prefLabel: c.prefLabel
-			})[0..{contentLimit}] as content
-		WHERE cm >= {minimumConnections}
+				prefLabel: c.prefLabel,
+				publishedDateEpoch: c.publishedDateEpoch,
+				labels: labels(c),
+				brands: [(c)-[:IS_CLASSIFIED_BY]->(b:Thing) | {uuid: b.uuid, prefLabel: b.prefLabel}],
+				relationships: relationships
+			})[0..$contentLimit] as content
+		WHERE cm >= $minimumConnections
 		WITH
 			p2.prefUUID as uuid,
 			p2.prefLabel as prefLabel,
 			cm as count,
-			content as contentList
+			score,
+			content as contentList%[7]s
 		RETURN
 			prefLabel,
 			uuid,
 			count,
+			score,
 			contentList
 		ORDER BY
+			score DESC,
 			count DESC,
 			uuid ASC
-		LIMIT {limit}
-	`
+		LIMIT $limit
+	`, sourceType, targetType, mentionWeight("a", filter), mentionWeight("a2", filter), mentionRelationships(filter), contentFilter("c", filter), pageFilter("count", page), contentSorts[contentSort], self)
 
 	query := &neoism.CypherQuery{
 		Statement: statement,
-		Parameters: neoism.Props{
+		Parameters: pageParameters(neoism.Props{
 			"uuid":               uuid,
 			"fromDate":           fromDateEpoch,
 			"toDate":             toDateEpoch,
 			"minimumConnections": minimumConnections,
-			"limit":              resultLimit,
 			"contentLimit":       contentLimit,
-		},
+			"minConfidence":      filter.MinConfidence,
+			"brands":             filter.Brands,
+			"contentTypes":       filter.ContentTypes,
+		}, page),
 		Result: &results,
 	}
 
-	if err := cd.conn.CypherBatch([]*neoism.CypherQuery{query}); err != nil || len(results) == 0 {
-		return []ConnectedPerson{}, false, err
-	}
-
-	return transformToConnectedPeople(&results), true, nil
+	err := cd.cypherBatch(ctx, []*neoism.CypherQuery{query})
+	return results, err
 }
 
 func transformToConnectedPeople(neo *[]neoConnectedPeopleReadStruct) []ConnectedPerson {
@@ -86,18 +176,61 @@ func transformToConnectedPeople(neo *[]neoConnectedPeopleReadStruct) []Connected
 		connectedPerson.Person.ID = mapper.IDURL(neoCP.UUID)
 		connectedPerson.Person.PrefLabel = neoCP.PrefLabel
 		connectedPerson.Count = neoCP.Count
+		connectedPerson.Score = neoCP.Score
+		connectedPerson.Content = transformToContentList(neoCP.ContentList)
+		connectedPeople = append(connectedPeople, connectedPerson)
+	}
+	return connectedPeople
+}
 
-		contentList := []Content{}
+func transformToConnectedConcepts(neo *[]neoConnectedPeopleReadStruct, conceptType string) []ConnectedConcept {
+	connectedConcepts := []ConnectedConcept{}
+	for _, neoCC := range *neo {
+		var connectedConcept = ConnectedConcept{}
+		connectedConcept.Concept.APIURL = mapper.APIURL(neoCC.UUID, []string{conceptType}, "local")
+		connectedConcept.Concept.ID = mapper.IDURL(neoCC.UUID)
+		connectedConcept.Concept.PrefLabel = neoCC.PrefLabel
+		connectedConcept.Count = neoCC.Count
+		connectedConcept.Score = neoCC.Score
+		connectedConcept.Content = transformToContentList(neoCC.ContentList)
+		connectedConcepts = append(connectedConcepts, connectedConcept)
+	}
+	return connectedConcepts
+}
 
-		for _, neoContent := range neoCP.ContentList {
-			var content = Content{}
-			content.ID = neoContent.UUID
-			content.Title = neoContent.PrefLabel
-			content.APIURL = mapper.APIURL(neoContent.UUID, []string{"Content"}, "local")
-			contentList = append(contentList, content)
+func transformToContentList(neo []neoContentReadStruct) []Content {
+	contentList := []Content{}
+	for _, neoContent := range neo {
+		var content = Content{}
+		content.ID = neoContent.UUID
+		content.Title = neoContent.PrefLabel
+		content.APIURL = mapper.APIURL(neoContent.UUID, []string{"Content"}, "local")
+		if neoContent.PublishedDateEpoch > 0 {
+			content.PublishedDate = time.Unix(neoContent.PublishedDateEpoch, 0).UTC().Format(time.RFC3339)
 		}
-		connectedPerson.Content = contentList
-		connectedPeople = append(connectedPeople, connectedPerson)
+		content.Type = contentType(neoContent.Labels)
+		for _, neoBrand := range neoContent.Brands {
+			content.Brands = append(content.Brands, Thing{
+				ID:        mapper.IDURL(neoBrand.UUID),
+				APIURL:    mapper.APIURL(neoBrand.UUID, []string{"Brand"}, "local"),
+				PrefLabel: neoBrand.PrefLabel,
+			})
+		}
+		content.Predicates = predicatesOf(neoContent.Relationships)
+		contentList = append(contentList, content)
 	}
-	return connectedPeople
+	return contentList
+}
+
+// The most specific label of content, like Article or Video, or Content when it has no other
+func contentType(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	for _, label := range labels {
+		if label != "Thing" && label != "Content" {
+			return label
+		}
+	}
+	return "Content"
 }
@@ -0,0 +1,122 @@
+package sixdegrees
+
+import (
+	"context"
+	"github.com/jmcvetta/neoism"
+)
+
+type neoConnectionCountReadStruct struct {
+	Count int `json:"count"`
+}
+
+// Connection pages through all the content co-mentioning two people, newest first, and counts it per day for the whole period.
+// Pages are keyed by the publishedDateEpoch and UUID of their last content, as its score and UUID
+func (cd CypherDriver) Connection(ctx context.Context, uuid string, otherUUID string, fromDateEpoch int64, toDateEpoch int64, page PageRequest) (Connection, error) {
+	people := []neoThingReadStruct{}
+	peopleQuery := &neoism.CypherQuery{
+		Statement: `
+			MATCH (p:Person)
+			WHERE p.prefUUID IN [$uuid, $otherUUID]
+			RETURN DISTINCT
+				p.prefUUID as uuid,
+				p.prefLabel as prefLabel
+		`,
+		Parameters: neoism.Props{
+			"uuid":      uuid,
+			"otherUUID": otherUUID,
+		},
+		Result: &people,
+	}
+
+	// The same pattern as connected people, between two given people
+	match := `
+			MATCH (c:Content)
+			WHERE
+				c.publishedDateEpoch < $toDate
+				AND c.publishedDateEpoch > $fromDate
+			MATCH (p:Person{prefUUID:$uuid})<-[:EQUIVALENT_TO]-(:Person)<-[:MENTIONS]-(c)
+			MATCH (c)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p2:Person{prefUUID:$otherUUID})
+			WITH DISTINCT c`
+	params := neoism.Props{
+		"uuid":          uuid,
+		"otherUUID":     otherUUID,
+		"fromDate":      fromDateEpoch,
+		"toDate":        toDateEpoch,
+		"secondsPerDay": secondsPerDay,
+	}
+
+	content := []neoContentReadStruct{}
+	contentQuery := &neoism.CypherQuery{
+		Statement: match + `
+			WITH
+				c.uuid as uuid,
+				c.prefLabel as prefLabel,
+				c.publishedDateEpoch as publishedDateEpoch,
+				labels(c) as labels` + contentPageFilter(page) + `
+			RETURN
+				uuid,
+				prefLabel,
+				publishedDateEpoch,
+				labels
+			ORDER BY
+				publishedDateEpoch DESC,
+				uuid ASC
+			LIMIT $limit
+		`,
+		Parameters: pageParameters(params, page),
+		Result:     &content,
+	}
+
+	days := []neoDailyMentionsReadStruct{}
+	histogramQuery := &neoism.CypherQuery{
+		Statement: match + `
+			WITH
+				c.publishedDateEpoch - c.publishedDateEpoch % $secondsPerDay as day,
+				c
+			RETURN
+				day,
+				count(c) as count
+			ORDER BY
+				day ASC
+		`,
+		Parameters: params,
+		Result:     &days,
+	}
+
+	err := cd.cypherBatch(ctx, []*neoism.CypherQuery{peopleQuery, contentQuery, histogramQuery})
+	if err != nil {
+		return Connection{}, err
+	}
+	if len(people) < 2 {
+		return Connection{}, ErrConceptNotFound
+	}
+
+	connection := Connection{
+		People:    []Thing{},
+		Content:   transformToContentList(content),
+		Histogram: bucketMentions(days, fromDateEpoch, toDateEpoch, "day"),
+	}
+	// In the order asked for, whatever the order they were read in
+	for _, wanted := range []string{uuid, otherUUID} {
+		for _, neoPerson := range people {
+			if neoPerson.UUID == wanted {
+				connection.People = append(connection.People, transformToPerson(neoPerson))
+			}
+		}
+	}
+	for _, day := range days {
+		connection.Count += day.Count
+	}
+	return connection, nil
+}
+
+// Keyset pagination of content ordered by publishedDateEpoch DESC, uuid ASC
+func contentPageFilter(page PageRequest) string {
+	if page.After == nil {
+		return ""
+	}
+	return `
+			WHERE
+				publishedDateEpoch < $afterScore
+				OR (publishedDateEpoch = $afterScore AND uuid > $afterUUID)`
+}
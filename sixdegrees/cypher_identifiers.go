@@ -0,0 +1,32 @@
+package sixdegrees
+
+import (
+	"context"
+	"github.com/jmcvetta/neoism"
+)
+
+// CanonicalUUID resolves a source UUID, or the value of any identifier node like a TME or FactSet one, to the prefUUID
+// of the concept it is equivalent to. Identifiers of more than one concept resolve to none
+func (cd CypherDriver) CanonicalUUID(ctx context.Context, identifier string) (string, bool, error) {
+	results := []struct {
+		UUID string `json:"uuid"`
+	}{}
+	query := &neoism.CypherQuery{
+		Statement: `
+			MATCH (:Thing{uuid:$identifier})-[:EQUIVALENT_TO]->(c:Thing)
+			RETURN c.prefUUID as uuid
+			UNION
+			MATCH (:Identifier{value:$identifier})-[:IDENTIFIES]->(:Thing)-[:EQUIVALENT_TO]->(c:Thing)
+			RETURN c.prefUUID as uuid
+		`,
+		Parameters: neoism.Props{
+			"identifier": identifier,
+		},
+		Result: &results,
+	}
+
+	if err := cd.cypherBatch(ctx, []*neoism.CypherQuery{query}); err != nil || len(results) != 1 {
+		return "", false, err
+	}
+	return results[0].UUID, true, nil
+}
@@ -1,13 +1,16 @@
 package sixdegrees
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/jmcvetta/neoism"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -23,6 +26,7 @@ const (
 	content2UUID            = "a435b4ec-b207-4dce-ac0a-f8e7bbef310b"
 	personSiobhanMordenUUID = "13a9d251-71db-467a-af2f-7e56a61c910a"
 	personBorisJohnsonUUID  = "b30ec30e-83ca-4e4a-b82f-db6f7a0bb16d"
+	brandUUID               = "dbb0bdae-1f0c-11e4-b0cb-b2227cce2b54"
 )
 
 type cypherTestCase struct {
@@ -31,8 +35,16 @@ type cypherTestCase struct {
 	uuid                              string
 	fromDateEpoch                     int64
 	toDateEpoch                       int64
-	makeConnectedPeopleAssertions     func(*testing.T, []ConnectedPerson, bool, error, string)
-	makeMostMentionedPeopleAssertions func(*testing.T, []Thing, bool, error, string)
+	makeConnectedPeopleAssertions     func(*testing.T, []ConnectedPerson, error, string)
+	makeMostMentionedPeopleAssertions func(*testing.T, []MentionedThing, bool, error, string)
+	makeShortestPathAssertions        func(*testing.T, ConnectionPath, bool, error, string)
+	makeNetworkAssertions             func(*testing.T, Network, bool, error, string)
+	makeTrendingAssertions            func(*testing.T, []TrendingThing, bool, error, string)
+	previousFromDateEpoch             int64
+	previousToDateEpoch               int64
+	interval                          string
+	makeMentionsAssertions            func(*testing.T, []MentionsBucket, bool, error, string)
+	filter                            MentionFilter
 }
 
 func TestConnectedPeople(t *testing.T) {
@@ -42,130 +54,841 @@ func TestConnectedPeople(t *testing.T) {
 	cleanDB(db, t)
 	defer cleanDB(db, t)
 
-	conceptsRW := concepts.NewConceptService(db)
-	require.NoError(t, conceptsRW.Initialise())
-	writeJsonToConceptsService(&conceptsRW, fmt.Sprintf("./fixtures/Person-Siobhan_Morden-%s.json", personSiobhanMordenUUID), t)
-	writeJsonToConceptsService(&conceptsRW, fmt.Sprintf("./fixtures/Person-Boris_Johnson-%s.json", personBorisJohnsonUUID), t)
-
-	contentRW := content.NewCypherContentService(db)
-	require.NoError(t, contentRW.Initialise())
-	writeJsonToService(contentRW, fmt.Sprintf("./fixtures/Content-%s.json", contentUUID), t)
-	writeJsonToService(contentRW, fmt.Sprintf("./fixtures/Content-%s.json", content2UUID), t)
-
-	annotationsRW := annotations.NewCypherAnnotationsService(db)
-	require.NoError(t, annotationsRW.Initialise())
-	writeJSONToAnnotationsService(annotationsRW, contentUUID, fmt.Sprintf("./fixtures/Annotations-%s-v2.json", contentUUID), t)
-	writeJSONToAnnotationsService(annotationsRW, content2UUID, fmt.Sprintf("./fixtures/Annotations-%s-v2.json", content2UUID), t)
+	writeFixtures(db, t)
 
 	tests := []cypherTestCase{
 		{
 			name: "SuccessWithConnectedPersonInTimeRange",
 			conn: interceptingCypherConn{db: db},
 			uuid: personBorisJohnsonUUID,
-			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, found bool, err error, testName string) {
+			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.Equal(t, getExpectedConnectedPeople(), connectedPeople, fmt.Sprintf("%s: Actual connected people are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"},
+		},
+		{
+			name: "SuccessWithRelevanceWeighting",
+			conn: interceptingCypherConn{db: db},
+			uuid: personBorisJohnsonUUID,
+			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				require.Len(t, connectedPeople, 1, fmt.Sprintf("%s: Wrong number of connected people", testName))
+				assert.Equal(t, 2, connectedPeople[0].Count, fmt.Sprintf("%s: Wrong count", testName))
+				assert.InDelta(t, 2*0.01807261511822952*0.12456035928791732, connectedPeople[0].Score, 0.000001, fmt.Sprintf("%s: Wrong score", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "relevance"},
+		},
+		{
+			name: "SuccessWithoutConfidentConnectedPerson",
+			conn: interceptingCypherConn{db: db},
+			uuid: personBorisJohnsonUUID,
+			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.Equal(t, []ConnectedPerson{}, connectedPeople, fmt.Sprintf("%s: Actual connected people are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count", MinConfidence: 0.9},
+		},
+		{
+			name: "SuccessWithConnectedPersonInBrand",
+			conn: interceptingCypherConn{db: db},
+			uuid: personBorisJohnsonUUID,
+			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, err error, testName string) {
 				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
-				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
 				assert.Equal(t, getExpectedConnectedPeople(), connectedPeople, fmt.Sprintf("%s: Actual connected people are different than expected", testName))
 			},
 			fromDateEpoch: getTimeEpoch("2016-12-12"),
 			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count", Brands: []string{"99999", brandUUID}, ContentTypes: []string{"Content"}},
+		},
+		{
+			name: "SuccessWithoutConnectedPersonInOtherBrand",
+			conn: interceptingCypherConn{db: db},
+			uuid: personBorisJohnsonUUID,
+			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.Equal(t, []ConnectedPerson{}, connectedPeople, fmt.Sprintf("%s: Actual connected people are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count", Brands: []string{"99999"}},
 		},
 		{
 			name: "SuccessWithoutConnectedPersonInTimeRange",
 			conn: interceptingCypherConn{db: db},
 			uuid: personBorisJohnsonUUID,
-			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, found bool, err error, testName string) {
+			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, err error, testName string) {
 				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
-				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
 				assert.Equal(t, []ConnectedPerson{}, connectedPeople, fmt.Sprintf("%s: Actual connected people are different than expected", testName))
 			},
 			fromDateEpoch: getTimeEpoch("2015-12-12"),
 			toDateEpoch:   getTimeEpoch("2015-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"},
+		},
+		{
+			name: "FailureWithUnknownPerson",
+			conn: interceptingCypherConn{db: db},
+			uuid: "99999",
+			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, err error, testName string) {
+				assert.Equal(t, ErrConceptNotFound, err, fmt.Sprintf("%s: Unknown person should not be found", testName))
+				assert.Equal(t, []ConnectedPerson{}, connectedPeople, fmt.Sprintf("%s: Actual connected people are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"},
 		},
 		{
 			name: "Failure",
 			conn: interceptingCypherConn{db: db, shouldFail: true},
 			uuid: personBorisJohnsonUUID,
-			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, found bool, err error, testName string) {
+			makeConnectedPeopleAssertions: func(t *testing.T, connectedPeople []ConnectedPerson, err error, testName string) {
 				assert.Error(t, err, fmt.Sprintf("%s: Error not found", testName))
-				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
 				assert.Equal(t, []ConnectedPerson{}, connectedPeople, fmt.Sprintf("%s: Actual connected people are different than expected", testName))
 			},
 			fromDateEpoch: getTimeEpoch("2015-12-12"),
 			toDateEpoch:   getTimeEpoch("2015-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"},
 		},
 	}
 
 	for _, test := range tests {
-		connectedPeople, found, err := CypherDriver{test.conn}.ConnectedPeople(test.uuid, test.fromDateEpoch, test.toDateEpoch, 1, 1, 5)
-		test.makeConnectedPeopleAssertions(t, connectedPeople, found, err, test.name)
+		connectedPeople, err := CypherDriver{test.conn}.ConnectedPeople(context.Background(), test.uuid, test.fromDateEpoch, test.toDateEpoch, PageRequest{Limit: 1}, 1, 5, "", test.filter)
+		test.makeConnectedPeopleAssertions(t, connectedPeople, err, test.name)
 	}
+
+	// The person is among their own connected people, as v1 has always answered, unlike connected concepts
+	connectedPeople, err := CypherDriver{db}.ConnectedPeople(context.Background(), personBorisJohnsonUUID, getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 5}, 1, 5, "", MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"})
+	assert.NoError(t, err)
+	ids := []string{}
+	for _, connectedPerson := range connectedPeople {
+		ids = append(ids, connectedPerson.Person.ID)
+	}
+	assert.ElementsMatch(t, []string{fmt.Sprintf("http://api.ft.com/things/%s", personSiobhanMordenUUID), fmt.Sprintf("http://api.ft.com/things/%s", personBorisJohnsonUUID)}, ids)
 }
 
-func TestMostMentionedPeople(t *testing.T) {
+func TestConnectedConcepts(t *testing.T) {
 	db := getDatabaseConnection(t)
 
 	//We want to make sure we have an empty DB before and after we run the tests
 	cleanDB(db, t)
 	defer cleanDB(db, t)
 
-	conceptsRW := concepts.NewConceptService(db)
-	require.NoError(t, conceptsRW.Initialise())
-	writeJsonToConceptsService(&conceptsRW, fmt.Sprintf("./fixtures/Person-Siobhan_Morden-%s.json", personSiobhanMordenUUID), t)
-	writeJsonToConceptsService(&conceptsRW, fmt.Sprintf("./fixtures/Person-Boris_Johnson-%s.json", personBorisJohnsonUUID), t)
+	writeFixtures(db, t)
 
-	contentRW := content.NewCypherContentService(db)
-	require.NoError(t, contentRW.Initialise())
-	writeJsonToService(contentRW, fmt.Sprintf("./fixtures/Content-%s.json", contentUUID), t)
-	writeJsonToService(contentRW, fmt.Sprintf("./fixtures/Content-%s.json", content2UUID), t)
+	connectedConcepts, err := CypherDriver{db}.ConnectedConcepts(context.Background(), personBorisJohnsonUUID, "Person", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 5}, 1, 5, "", MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"})
+	assert.NoError(t, err)
+	expected := getExpectedConnectedPeople()[0]
+	assert.Equal(t, []ConnectedConcept{{Concept: expected.Person, Count: expected.Count, Score: expected.Score, Content: expected.Content}}, connectedConcepts)
 
-	annotationsRW := annotations.NewCypherAnnotationsService(db)
-	require.NoError(t, annotationsRW.Initialise())
-	writeJSONToAnnotationsService(annotationsRW, contentUUID, fmt.Sprintf("./fixtures/Annotations-%s-v2.json", contentUUID), t)
-	writeJSONToAnnotationsService(annotationsRW, content2UUID, fmt.Sprintf("./fixtures/Annotations-%s-v2.json", content2UUID), t)
+	connectedConcepts, err = CypherDriver{db}.ConnectedConcepts(context.Background(), personBorisJohnsonUUID, "Organisation", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 5}, 1, 5, "", MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"})
+	assert.NoError(t, err)
+	assert.Equal(t, []ConnectedConcept{}, connectedConcepts)
+
+	_, err = CypherDriver{db}.ConnectedConcepts(context.Background(), "99999", "Organisation", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 5}, 1, 5, "", MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"})
+	assert.Equal(t, ErrConceptNotFound, err)
+
+	_, err = CypherDriver{db}.ConnectedConcepts(context.Background(), personBorisJohnsonUUID, "Thing", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 5}, 1, 5, "", MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"})
+	assert.Error(t, err)
+
+	connectedConcepts, err = CypherDriver{db}.ConnectedConcepts(context.Background(), personBorisJohnsonUUID, "Person", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 5}, 1, 5, "", MentionFilter{Predicates: []string{"about", "hasAuthor"}, Weighting: "count"})
+	assert.NoError(t, err)
+	assert.Equal(t, []ConnectedConcept{}, connectedConcepts)
+
+	connectedConcepts, err = CypherDriver{db}.ConnectedConcepts(context.Background(), personBorisJohnsonUUID, "Person", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 5}, 1, 5, "", MentionFilter{Predicates: []string{"about", "mentions"}, Weighting: "count"})
+	assert.NoError(t, err)
+	assert.Equal(t, []ConnectedConcept{{Concept: expected.Person, Count: expected.Count, Score: expected.Score, Content: expected.Content}}, connectedConcepts)
+
+	_, err = CypherDriver{db}.ConnectedConcepts(context.Background(), personBorisJohnsonUUID, "Person", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 5}, 1, 5, "", MentionFilter{Predicates: []string{"MENTIONS]-()-[:ABOUT"}, Weighting: "count"})
+	assert.Error(t, err)
+}
+
+func TestConnectedPeopleContentSort(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	tests := []struct {
+		contentSort     string
+		expectedContent []string
+	}{
+		{"newest", []string{content2UUID, contentUUID}},
+		{"oldest", []string{contentUUID, content2UUID}},
+		{"newest", []string{content2UUID}},
+	}
+
+	for i, test := range tests {
+		contentLimit := len(test.expectedContent)
+		connectedPeople, err := CypherDriver{db}.ConnectedPeople(context.Background(), personBorisJohnsonUUID, getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 1}, 1, contentLimit, test.contentSort, MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"})
+		assert.NoError(t, err, "%d: Error found", i)
+		require.Len(t, connectedPeople, 1, "%d: Wrong number of connected people", i)
+
+		content := []string{}
+		for _, c := range connectedPeople[0].Content {
+			content = append(content, c.ID)
+		}
+		assert.Equal(t, test.expectedContent, content, "%d: Wrong content sorted by %s", i, test.contentSort)
+	}
+
+	connectedPeople, err := CypherDriver{db}.ConnectedPeople(context.Background(), personBorisJohnsonUUID, getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 1}, 1, 5, "relevance", MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"})
+	assert.NoError(t, err)
+	require.Len(t, connectedPeople, 1)
+	assert.Len(t, connectedPeople[0].Content, 2, "Sorting by relevance should keep all content")
+
+	_, err = CypherDriver{db}.ConnectedPeople(context.Background(), personBorisJohnsonUUID, getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 1}, 1, 5, "c.uuid; MATCH (n) DETACH DELETE n", MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"})
+	assert.Error(t, err, "Unknown content sorts should never reach Cypher")
+}
+
+func TestSearchPeople(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+	require.NoError(t, EnsurePeopleSearchIndex(db))
+
+	borisJohnson := Thing{
+		ID:        "http://api.ft.com/things/" + personBorisJohnsonUUID,
+		APIURL:    "http://api.ft.com/people/" + personBorisJohnsonUUID,
+		PrefLabel: "Boris Johnson",
+	}
+
+	tests := []struct {
+		query    string
+		expected []MatchedThing
+	}{
+		{"bor", []MatchedThing{{Thing: borisJohnson, Match: "Boris Johnson", Mentions: 2}}},
+		{"  JOHN", []MatchedThing{{Thing: borisJohnson, Match: "Boris Johnson", Mentions: 2}}},
+		{"alexander b", []MatchedThing{{Thing: borisJohnson, Match: "Alexander Boris de Pfeffel Johnson", Mentions: 2}}},
+		{"pfeffel", []MatchedThing{{Thing: borisJohnson, Match: "Alexander Boris de Pfeffel Johnson", Mentions: 2}}},
+		{"oris", []MatchedThing{}},
+	}
+
+	for _, test := range tests {
+		matched, err := CypherDriver{db}.SearchPeople(context.Background(), test.query, getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), 10)
+		assert.NoError(t, err, "%s: Error found", test.query)
+		assert.Equal(t, test.expected, matched, "%s: Wrong people found", test.query)
+	}
+
+	matched, err := CypherDriver{db}.SearchPeople(context.Background(), "bor", getTimeEpoch("2015-12-12"), getTimeEpoch("2015-12-16"), 10)
+	assert.NoError(t, err)
+	require.Len(t, matched, 1)
+	assert.Equal(t, 0, matched[0].Mentions, "People should be found however little mentioned in the period")
+}
+
+func TestCanonicalUUID(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	tests := []struct {
+		identifier    string
+		expectedUUID  string
+		expectedFound bool
+	}{
+		{personBorisJohnsonUUID, personBorisJohnsonUUID, true},
+		{"91ABCD-F", personBorisJohnsonUUID, true},
+		{"unknown", "", false},
+	}
+
+	for _, test := range tests {
+		canonicalUUID, found, err := CypherDriver{db}.CanonicalUUID(context.Background(), test.identifier)
+		assert.NoError(t, err, "%s: Error found", test.identifier)
+		assert.Equal(t, test.expectedFound, found, "%s: Wrong found", test.identifier)
+		assert.Equal(t, test.expectedUUID, canonicalUUID, "%s: Wrong canonical UUID", test.identifier)
+	}
+}
+
+func TestMostMentionedPeople(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
 
 	tests := []cypherTestCase{
 		{
 			name: "SuccessWithMostMentionedInTimeRange",
 			conn: interceptingCypherConn{db: db},
-			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []Thing, found bool, err error, testName string) {
+			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []MentionedThing, found bool, err error, testName string) {
 				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
 				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
 				assert.Equal(t, getExpectedMostMentionedPeople(), mentionedPeople, fmt.Sprintf("%s: Actual most mentioned people are different than expected", testName))
 			},
 			fromDateEpoch: getTimeEpoch("2016-12-12"),
 			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"},
+		},
+		{
+			name: "SuccessWithMostRelevantInTimeRange",
+			conn: interceptingCypherConn{db: db},
+			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []MentionedThing, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
+				require.Len(t, mentionedPeople, 2, fmt.Sprintf("%s: Wrong number of most mentioned people", testName))
+				assert.Equal(t, "Siobhan Morden", mentionedPeople[0].PrefLabel, fmt.Sprintf("%s: Wrong most relevant person", testName))
+				assert.InDelta(t, 2*0.12456035928791732, mentionedPeople[0].Score, 0.000001, fmt.Sprintf("%s: Wrong score", testName))
+				assert.InDelta(t, 2*0.01807261511822952, mentionedPeople[1].Score, 0.000001, fmt.Sprintf("%s: Wrong score", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "relevance"},
+		},
+		{
+			name: "SuccessWithConfidentlyMentionedInTimeRange",
+			conn: interceptingCypherConn{db: db},
+			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []MentionedThing, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
+				assert.Equal(t, getExpectedMostMentionedPeople()[1:], mentionedPeople, fmt.Sprintf("%s: Actual most mentioned people are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count", MinConfidence: 0.9},
+		},
+		{
+			name: "SuccessWithoutMostMentionedForOtherPredicates",
+			conn: interceptingCypherConn{db: db},
+			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []MentionedThing, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, []MentionedThing{}, mentionedPeople, fmt.Sprintf("%s: Actual most mentioned people are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"about", "hasAuthor"}, Weighting: "count"},
+		},
+		{
+			name: "SuccessWithoutMostMentionedInOtherContentTypes",
+			conn: interceptingCypherConn{db: db},
+			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []MentionedThing, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, []MentionedThing{}, mentionedPeople, fmt.Sprintf("%s: Actual most mentioned people are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count", ContentTypes: []string{"Video", "LiveBlogPost"}},
 		},
 		{
 			name: "SuccessWithoutConnectedPersonInTimeRange",
 			conn: interceptingCypherConn{db: db},
-			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []Thing, found bool, err error, testName string) {
+			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []MentionedThing, found bool, err error, testName string) {
 				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
 				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
-				assert.Equal(t, []Thing{}, mentionedPeople, fmt.Sprintf("%s: Actual most mentioned people are different than expected", testName))
+				assert.Equal(t, []MentionedThing{}, mentionedPeople, fmt.Sprintf("%s: Actual most mentioned people are different than expected", testName))
 			},
 			fromDateEpoch: getTimeEpoch("2015-12-12"),
 			toDateEpoch:   getTimeEpoch("2015-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"},
 		},
 		{
 			name: "Failure",
 			conn: interceptingCypherConn{db: db, shouldFail: true},
-			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []Thing, found bool, err error, testName string) {
+			makeMostMentionedPeopleAssertions: func(t *testing.T, mentionedPeople []MentionedThing, found bool, err error, testName string) {
 				assert.Error(t, err, fmt.Sprintf("%s: Error not found", testName))
 				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
-				assert.Equal(t, []Thing{}, mentionedPeople, fmt.Sprintf("%s: Actual most mentioned people are different than expected", testName))
+				assert.Equal(t, []MentionedThing{}, mentionedPeople, fmt.Sprintf("%s: Actual most mentioned people are different than expected", testName))
 			},
 			fromDateEpoch: getTimeEpoch("2015-12-12"),
 			toDateEpoch:   getTimeEpoch("2015-12-16"),
+			filter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"},
 		},
 	}
 
 	for _, test := range tests {
-		thingList, found, err := CypherDriver{test.conn}.MostMentioned(test.fromDateEpoch, test.toDateEpoch, 5)
+		thingList, found, err := CypherDriver{test.conn}.MostMentioned(context.Background(), "Person", test.fromDateEpoch, test.toDateEpoch, PageRequest{Limit: 5}, test.filter)
 		test.makeMostMentionedPeopleAssertions(t, thingList, found, err, test.name)
 	}
 }
 
+func TestMostMentionedPeoplePages(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	filter := MentionFilter{Predicates: []string{"mentions"}, Weighting: "count"}
+	expected := getExpectedMostMentionedPeople()
+
+	// The first page holds one more result than its limit, telling there is a next page
+	firstPage, found, err := CypherDriver{db}.MostMentioned(context.Background(), "Person", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 1}, filter)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, expected, firstPage)
+
+	after := &PageKey{Score: expected[0].Score, Count: expected[0].Mentions, UUID: personSiobhanMordenUUID}
+	lastPage, found, err := CypherDriver{db}.MostMentioned(context.Background(), "Person", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 1, After: after}, filter)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, expected[1:], lastPage)
+
+	after = &PageKey{Score: expected[1].Score, Count: expected[1].Mentions, UUID: personBorisJohnsonUUID}
+	_, found, err = CypherDriver{db}.MostMentioned(context.Background(), "Person", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 1, After: after}, filter)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestTrending(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	tests := []cypherTestCase{
+		{
+			name: "SuccessWithNewMentionsInTimeRange",
+			conn: interceptingCypherConn{db: db},
+			makeTrendingAssertions: func(t *testing.T, trending []TrendingThing, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
+				assert.Equal(t, getExpectedTrendingPeople(1, 0), trending, fmt.Sprintf("%s: Actual trending people are different than expected", testName))
+			},
+			fromDateEpoch:         getTimeEpoch("2016-12-15"),
+			toDateEpoch:           getTimeEpoch("2016-12-16"),
+			previousFromDateEpoch: getTimeEpoch("2016-12-14"),
+			previousToDateEpoch:   getTimeEpoch("2016-12-15"),
+		},
+		{
+			name: "SuccessWithSameMentionsInBothTimeRanges",
+			conn: interceptingCypherConn{db: db},
+			makeTrendingAssertions: func(t *testing.T, trending []TrendingThing, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
+				assert.Equal(t, getExpectedTrendingPeople(1, 1), trending, fmt.Sprintf("%s: Actual trending people are different than expected", testName))
+			},
+			fromDateEpoch:         getTimeEpoch("2016-12-14"),
+			toDateEpoch:           getTimeEpoch("2016-12-16"),
+			previousFromDateEpoch: getTimeEpoch("2016-12-12"),
+			previousToDateEpoch:   getTimeEpoch("2016-12-14"),
+		},
+		{
+			name: "SuccessWithoutMentionsInTimeRange",
+			conn: interceptingCypherConn{db: db},
+			makeTrendingAssertions: func(t *testing.T, trending []TrendingThing, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, []TrendingThing{}, trending, fmt.Sprintf("%s: Actual trending people are different than expected", testName))
+			},
+			fromDateEpoch:         getTimeEpoch("2016-12-16"),
+			toDateEpoch:           getTimeEpoch("2016-12-20"),
+			previousFromDateEpoch: getTimeEpoch("2016-12-12"),
+			previousToDateEpoch:   getTimeEpoch("2016-12-16"),
+		},
+		{
+			name: "Failure",
+			conn: interceptingCypherConn{db: db, shouldFail: true},
+			makeTrendingAssertions: func(t *testing.T, trending []TrendingThing, found bool, err error, testName string) {
+				assert.Error(t, err, fmt.Sprintf("%s: Error not found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, []TrendingThing{}, trending, fmt.Sprintf("%s: Actual trending people are different than expected", testName))
+			},
+			fromDateEpoch:         getTimeEpoch("2016-12-15"),
+			toDateEpoch:           getTimeEpoch("2016-12-16"),
+			previousFromDateEpoch: getTimeEpoch("2016-12-14"),
+			previousToDateEpoch:   getTimeEpoch("2016-12-15"),
+		},
+	}
+
+	for _, test := range tests {
+		for _, rankBy := range []string{"absolute", "relative"} {
+			trending, found, err := CypherDriver{test.conn}.Trending(context.Background(), "Person", test.fromDateEpoch, test.toDateEpoch, test.previousFromDateEpoch, test.previousToDateEpoch, 5, rankBy)
+			test.makeTrendingAssertions(t, trending, found, err, fmt.Sprintf("%s/%s", test.name, rankBy))
+		}
+	}
+}
+
+func TestMentions(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	tests := []cypherTestCase{
+		{
+			name:     "SuccessWithDailyMentions",
+			conn:     interceptingCypherConn{db: db},
+			uuid:     personBorisJohnsonUUID,
+			interval: "day",
+			makeMentionsAssertions: func(t *testing.T, buckets []MentionsBucket, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
+				assert.Equal(t, []MentionsBucket{
+					{Date: "2016-12-12", Count: 0},
+					{Date: "2016-12-13", Count: 1},
+					{Date: "2016-12-14", Count: 0},
+					{Date: "2016-12-15", Count: 1},
+				}, buckets, fmt.Sprintf("%s: Actual mentions are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+		},
+		{
+			name:     "SuccessWithMonthlyMentions",
+			conn:     interceptingCypherConn{db: db},
+			uuid:     personBorisJohnsonUUID,
+			interval: "month",
+			makeMentionsAssertions: func(t *testing.T, buckets []MentionsBucket, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
+				assert.Equal(t, []MentionsBucket{
+					{Date: "2016-11-01", Count: 0},
+					{Date: "2016-12-01", Count: 2},
+				}, buckets, fmt.Sprintf("%s: Actual mentions are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-11-20"),
+			toDateEpoch:   getTimeEpoch("2016-12-20"),
+		},
+		{
+			name:     "SuccessWithoutMentionsInTimeRange",
+			conn:     interceptingCypherConn{db: db},
+			uuid:     personBorisJohnsonUUID,
+			interval: "week",
+			makeMentionsAssertions: func(t *testing.T, buckets []MentionsBucket, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
+				assert.Equal(t, []MentionsBucket{
+					{Date: "2015-12-07", Count: 0},
+					{Date: "2015-12-14", Count: 0},
+				}, buckets, fmt.Sprintf("%s: Actual mentions are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2015-12-12"),
+			toDateEpoch:   getTimeEpoch("2015-12-16"),
+		},
+		{
+			name:     "NotFound",
+			conn:     interceptingCypherConn{db: db},
+			uuid:     "99999",
+			interval: "day",
+			makeMentionsAssertions: func(t *testing.T, buckets []MentionsBucket, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, []MentionsBucket{}, buckets, fmt.Sprintf("%s: Actual mentions are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+		},
+		{
+			name:     "Failure",
+			conn:     interceptingCypherConn{db: db, shouldFail: true},
+			uuid:     personBorisJohnsonUUID,
+			interval: "day",
+			makeMentionsAssertions: func(t *testing.T, buckets []MentionsBucket, found bool, err error, testName string) {
+				assert.Error(t, err, fmt.Sprintf("%s: Error not found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, []MentionsBucket{}, buckets, fmt.Sprintf("%s: Actual mentions are different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+		},
+	}
+
+	for _, test := range tests {
+		buckets, found, err := CypherDriver{test.conn}.Mentions(context.Background(), test.uuid, test.fromDateEpoch, test.toDateEpoch, test.interval)
+		test.makeMentionsAssertions(t, buckets, found, err, test.name)
+	}
+}
+
+func TestCoMentionGraph(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	siobhanMorden := Thing{
+		ID:        fmt.Sprintf("http://api.ft.com/things/%s", personSiobhanMordenUUID),
+		APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personSiobhanMordenUUID),
+		PrefLabel: "Siobhan Morden",
+	}
+	borisJohnson := Thing{
+		ID:        fmt.Sprintf("http://api.ft.com/things/%s", personBorisJohnsonUUID),
+		APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personBorisJohnsonUUID),
+		PrefLabel: "Boris Johnson",
+	}
+
+	graph, found, err := CypherDriver{db}.CoMentionGraph(context.Background(), getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), 5, 1)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, CoMentionGraph{
+		People: []Thing{siobhanMorden, borisJohnson},
+		Edges:  []NetworkEdge{{Source: siobhanMorden.ID, Target: borisJohnson.ID, Count: 2, Content: []string{contentUUID, content2UUID}}},
+	}, graph)
+	assert.Equal(t, []Community{{Label: "Siobhan Morden", Members: []Thing{siobhanMorden, borisJohnson}, InternalWeight: 2}}, detectCommunities(graph))
+
+	graph, found, err = CypherDriver{db}.CoMentionGraph(context.Background(), getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), 1, 1)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, CoMentionGraph{People: []Thing{siobhanMorden}, Edges: []NetworkEdge{}}, graph)
+
+	graph, found, err = CypherDriver{db}.CoMentionGraph(context.Background(), getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), 5, 3)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, CoMentionGraph{People: []Thing{siobhanMorden, borisJohnson}, Edges: []NetworkEdge{}}, graph)
+
+	_, found, err = CypherDriver{db}.CoMentionGraph(context.Background(), getTimeEpoch("2015-12-12"), getTimeEpoch("2015-12-16"), 5, 1)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	_, found, err = CypherDriver{interceptingCypherConn{db: db, shouldFail: true}}.CoMentionGraph(context.Background(), getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), 5, 1)
+	assert.Error(t, err)
+	assert.False(t, found)
+}
+
+func TestSummedSnapshots(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	// Builds every day of the window but its last, which is queried live like today
+	days := SnapshotDays(time.Unix(getTimeEpoch("2016-12-16"), 0), 4)
+	require.NoError(t, BuildSnapshots(db, days))
+	require.NoError(t, BuildSnapshots(db, days), "Snapshots should be rebuilt in place")
+
+	snapshots := NewSnapshotDriver(CypherDriver{db}, db, 24*time.Hour)
+	fromDateEpoch, toDateEpoch := getTimeEpoch("2016-12-12")-1, getTimeEpoch("2016-12-17")
+
+	for _, weighting := range []string{"count", "relevance", "confidence"} {
+		filter := MentionFilter{Weighting: weighting, Predicates: []string{"mentions"}}
+		expected, found, err := CypherDriver{db}.MostMentioned(context.Background(), "Person", fromDateEpoch, toDateEpoch, PageRequest{Limit: 5}, filter)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		actual, found, err := snapshots.MostMentioned(context.Background(), "Person", fromDateEpoch, toDateEpoch, PageRequest{Limit: 5}, filter)
+		assert.NoError(t, err, weighting)
+		assert.True(t, found, weighting)
+		assert.Equal(t, expected, actual, "Summed snapshots should add up to live mentions weighted by %s", weighting)
+	}
+
+	expected, found, err := CypherDriver{db}.CoMentionGraph(context.Background(), fromDateEpoch, toDateEpoch, 5, 1)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	actual, found, err := snapshots.CoMentionGraph(context.Background(), fromDateEpoch, toDateEpoch, 5, 1)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, expected, actual, "Summed snapshots should add up to live co-mentions")
+}
+
+func TestShortestPath(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	tests := []cypherTestCase{
+		{
+			name: "SuccessWithPathInTimeRange",
+			conn: interceptingCypherConn{db: db},
+			uuid: personBorisJohnsonUUID,
+			makeShortestPathAssertions: func(t *testing.T, path ConnectionPath, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
+				assert.Equal(t, getExpectedShortestPath(), path, fmt.Sprintf("%s: Actual path is different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+		},
+		{
+			name: "SuccessWithoutPathInTimeRange",
+			conn: interceptingCypherConn{db: db},
+			uuid: personBorisJohnsonUUID,
+			makeShortestPathAssertions: func(t *testing.T, path ConnectionPath, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, ConnectionPath{}, path, fmt.Sprintf("%s: Actual path is different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2015-12-12"),
+			toDateEpoch:   getTimeEpoch("2015-12-16"),
+		},
+		{
+			name: "Failure",
+			conn: interceptingCypherConn{db: db, shouldFail: true},
+			uuid: personBorisJohnsonUUID,
+			makeShortestPathAssertions: func(t *testing.T, path ConnectionPath, found bool, err error, testName string) {
+				assert.Error(t, err, fmt.Sprintf("%s: Error not found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, ConnectionPath{}, path, fmt.Sprintf("%s: Actual path is different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2015-12-12"),
+			toDateEpoch:   getTimeEpoch("2015-12-16"),
+		},
+	}
+
+	for _, test := range tests {
+		path, found, err := CypherDriver{test.conn}.ShortestPath(context.Background(), test.uuid, personSiobhanMordenUUID, test.fromDateEpoch, test.toDateEpoch, 3, 5)
+		test.makeShortestPathAssertions(t, path, found, err, test.name)
+	}
+}
+
+func TestConnection(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	driver := CypherDriver{db}
+	connection, err := driver.Connection(context.Background(), personSiobhanMordenUUID, personBorisJohnsonUUID, getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Siobhan Morden", "Boris Johnson"}, []string{connection.People[0].PrefLabel, connection.People[1].PrefLabel}, "People should be in the order asked for")
+	assert.Equal(t, 2, connection.Count, "Count should cover all the shared content, not just its first page")
+	require.Len(t, connection.Content, 2, "One more content than the limit should tell there is a next page")
+	assert.Equal(t, content2UUID, connection.Content[0].ID, "Newest content should come first")
+	assert.Equal(t, "Learn Golang", connection.Content[0].Title)
+	assert.Equal(t, "2016-12-15T19:18:01Z", connection.Content[0].PublishedDate)
+
+	histogram := map[string]int{}
+	for _, bucket := range connection.Histogram {
+		if bucket.Count > 0 {
+			histogram[bucket.Date] = bucket.Count
+		}
+	}
+	assert.Equal(t, map[string]int{"2016-12-13": 1, "2016-12-15": 1}, histogram, "Wrong co-mentions per day")
+
+	after := PageKey{Score: float64(getTimeEpoch("2016-12-15") + 19*3600 + 18*60 + 1), UUID: content2UUID}
+	connection, err = driver.Connection(context.Background(), personSiobhanMordenUUID, personBorisJohnsonUUID, getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 1, After: &after})
+	assert.NoError(t, err)
+	require.Len(t, connection.Content, 1, "Next page should hold the rest of the content")
+	assert.Equal(t, contentUUID, connection.Content[0].ID)
+
+	connection, err = driver.Connection(context.Background(), personSiobhanMordenUUID, personBorisJohnsonUUID, getTimeEpoch("2015-12-12"), getTimeEpoch("2015-12-16"), PageRequest{Limit: 1})
+	assert.NoError(t, err, "Known people should be found outside of the period they are co-mentioned in")
+	assert.Empty(t, connection.Content)
+	assert.Equal(t, 0, connection.Count)
+
+	_, err = driver.Connection(context.Background(), personSiobhanMordenUUID, "99999", getTimeEpoch("2016-12-12"), getTimeEpoch("2016-12-16"), PageRequest{Limit: 1})
+	assert.Equal(t, ErrConceptNotFound, err, "Unknown people should not be found")
+}
+
+func TestNetwork(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	//We want to make sure we have an empty DB before and after we run the tests
+	cleanDB(db, t)
+	defer cleanDB(db, t)
+
+	writeFixtures(db, t)
+
+	tests := []cypherTestCase{
+		{
+			name: "SuccessWithNetworkInTimeRange",
+			conn: interceptingCypherConn{db: db},
+			uuid: personBorisJohnsonUUID,
+			makeNetworkAssertions: func(t *testing.T, network Network, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.True(t, found, fmt.Sprintf("%s: No result found", testName))
+				assert.Equal(t, getExpectedNetwork(), network, fmt.Sprintf("%s: Actual network is different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2016-12-12"),
+			toDateEpoch:   getTimeEpoch("2016-12-16"),
+		},
+		{
+			name: "SuccessWithoutNetworkInTimeRange",
+			conn: interceptingCypherConn{db: db},
+			uuid: personBorisJohnsonUUID,
+			makeNetworkAssertions: func(t *testing.T, network Network, found bool, err error, testName string) {
+				assert.NoError(t, err, fmt.Sprintf("%s: Error found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, Network{}, network, fmt.Sprintf("%s: Actual network is different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2015-12-12"),
+			toDateEpoch:   getTimeEpoch("2015-12-16"),
+		},
+		{
+			name: "Failure",
+			conn: interceptingCypherConn{db: db, shouldFail: true},
+			uuid: personBorisJohnsonUUID,
+			makeNetworkAssertions: func(t *testing.T, network Network, found bool, err error, testName string) {
+				assert.Error(t, err, fmt.Sprintf("%s: Error not found", testName))
+				assert.False(t, found, fmt.Sprintf("%s: Result was found", testName))
+				assert.Equal(t, Network{}, network, fmt.Sprintf("%s: Actual network is different than expected", testName))
+			},
+			fromDateEpoch: getTimeEpoch("2015-12-12"),
+			toDateEpoch:   getTimeEpoch("2015-12-16"),
+		},
+	}
+
+	for _, test := range tests {
+		network, found, err := CypherDriver{test.conn}.Network(context.Background(), test.uuid, test.fromDateEpoch, test.toDateEpoch, 2, 5, 1)
+		test.makeNetworkAssertions(t, network, found, err, test.name)
+	}
+}
+
+func TestCancelledQueriesStop(t *testing.T) {
+	db := getDatabaseConnection(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	err := CypherDriver{db}.cypherBatch(ctx, []*neoism.CypherQuery{{
+		Statement: `UNWIND range(1, 2000000000) AS i WITH i WHERE i < 0 RETURN count(i) AS stillRunning`,
+	}})
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// Neo4j is given a few seconds to notice, while the query would take minutes to finish
+	running := -1
+	for i := 0; i < 20 && running != 0; i++ {
+		time.Sleep(500 * time.Millisecond)
+		running = countRunningQueries(t, db, "stillRunning")
+	}
+	assert.Equal(t, 0, running, "The cancelled query should have stopped")
+}
+
+// Neo4j 4.4 and later list their queries by transaction, while 3 still lists them as queries
+func countRunningQueries(t *testing.T, db neoutils.NeoConnection, text string) int {
+	statement := `CALL dbms.listQueries() YIELD query WHERE query CONTAINS $text RETURN count(*) as count`
+	if _, ok := db.(*BoltConnection); ok {
+		statement = `SHOW TRANSACTIONS YIELD currentQuery WHERE currentQuery CONTAINS $text RETURN count(*) as count`
+	}
+	results := []struct {
+		Count int `json:"count"`
+	}{}
+	require.NoError(t, db.CypherBatch([]*neoism.CypherQuery{{
+		Statement:  statement,
+		Parameters: neoism.Props{"text": text},
+		Result:     &results,
+	}}))
+	require.Len(t, results, 1)
+	return results[0].Count
+}
+
 type interceptingCypherConn struct {
 	db         neoutils.NeoConnection
 	shouldFail bool
@@ -186,6 +909,44 @@ func (c interceptingCypherConn) EnsureIndexes(indexes map[string]string) error {
 	return c.db.EnsureIndexes(indexes)
 }
 
+// The writers of the fixtures are still on the {param} syntax removed in Neo4j 4, so their statements are moved to
+// $param when written over Bolt
+var legacyParameter = regexp.MustCompile(`\{(\w+)\}`)
+
+type legacyParameterConn struct {
+	neoutils.NeoConnection
+}
+
+func (c legacyParameterConn) CypherBatch(cypher []*neoism.CypherQuery) error {
+	queries := make([]*neoism.CypherQuery, len(cypher))
+	for i, query := range cypher {
+		rewritten := *query
+		rewritten.Statement = legacyParameter.ReplaceAllString(query.Statement, "$$$1")
+		queries[i] = &rewritten
+	}
+	return c.NeoConnection.CypherBatch(queries)
+}
+
+func writeFixtures(db neoutils.NeoConnection, t *testing.T) {
+	if _, ok := db.(*BoltConnection); ok {
+		db = legacyParameterConn{db}
+	}
+	conceptsRW := concepts.NewConceptService(db)
+	require.NoError(t, conceptsRW.Initialise())
+	writeJsonToConceptsService(&conceptsRW, fmt.Sprintf("./fixtures/Person-Siobhan_Morden-%s.json", personSiobhanMordenUUID), t)
+	writeJsonToConceptsService(&conceptsRW, fmt.Sprintf("./fixtures/Person-Boris_Johnson-%s.json", personBorisJohnsonUUID), t)
+
+	contentRW := content.NewCypherContentService(db)
+	require.NoError(t, contentRW.Initialise())
+	writeJsonToService(contentRW, fmt.Sprintf("./fixtures/Content-%s.json", contentUUID), t)
+	writeJsonToService(contentRW, fmt.Sprintf("./fixtures/Content-%s.json", content2UUID), t)
+
+	annotationsRW := annotations.NewCypherAnnotationsService(db)
+	require.NoError(t, annotationsRW.Initialise())
+	writeJSONToAnnotationsService(annotationsRW, contentUUID, fmt.Sprintf("./fixtures/Annotations-%s-v2.json", contentUUID), t)
+	writeJSONToAnnotationsService(annotationsRW, content2UUID, fmt.Sprintf("./fixtures/Annotations-%s-v2.json", content2UUID), t)
+}
+
 func writeJsonToService(service baseftrwapp.Service, pathToJsonFile string, t *testing.T) {
 	f, err := os.Open(pathToJsonFile)
 	assert.NoError(t, err)
@@ -215,31 +976,150 @@ func getExpectedConnectedPeople() []ConnectedPerson {
 				PrefLabel: "Siobhan Morden",
 			},
 			Count: 2,
+			Score: 2,
 			Content: []Content{
 				{
-					ID:     "3fc9fe3e-af8c-4f7f-961a-e5065392bb31",
-					APIURL: "http://api.ft.com/content/3fc9fe3e-af8c-4f7f-961a-e5065392bb31",
-					Title:  "Bitcoin story makes Newsweek the headline",
+					ID:            "3fc9fe3e-af8c-4f7f-961a-e5065392bb31",
+					APIURL:        "http://api.ft.com/content/3fc9fe3e-af8c-4f7f-961a-e5065392bb31",
+					Title:         "Bitcoin story makes Newsweek the headline",
+					PublishedDate: "2016-12-13T19:18:01Z",
+					Type:          "Content",
+					Brands:        []Thing{{ID: "http://api.ft.com/things/" + brandUUID, APIURL: "http://api.ft.com/brands/" + brandUUID}},
+					Predicates:    []string{"mentions"},
 				},
 				{
-					ID:     "a435b4ec-b207-4dce-ac0a-f8e7bbef310b",
-					APIURL: "http://api.ft.com/content/a435b4ec-b207-4dce-ac0a-f8e7bbef310b",
-					Title:  "Learn Golang",
+					ID:            "a435b4ec-b207-4dce-ac0a-f8e7bbef310b",
+					APIURL:        "http://api.ft.com/content/a435b4ec-b207-4dce-ac0a-f8e7bbef310b",
+					Title:         "Learn Golang",
+					PublishedDate: "2016-12-15T19:18:01Z",
+					Type:          "Content",
+					Brands:        []Thing{{ID: "http://api.ft.com/things/" + brandUUID, APIURL: "http://api.ft.com/brands/" + brandUUID}},
+					Predicates:    []string{"mentions"},
 				},
 			},
 		},
 	}
 }
 
-func getExpectedMostMentionedPeople() []Thing {
-	return []Thing{
+func getExpectedMostMentionedPeople() []MentionedThing {
+	return []MentionedThing{
 		{
-			ID:        fmt.Sprintf("http://api.ft.com/things/%s", personSiobhanMordenUUID),
-			PrefLabel: "Siobhan Morden",
+			Thing: Thing{
+				ID:        fmt.Sprintf("http://api.ft.com/things/%s", personSiobhanMordenUUID),
+				APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personSiobhanMordenUUID),
+				PrefLabel: "Siobhan Morden",
+			},
+			Mentions:     2,
+			ContentCount: 2,
+			Score:        2,
 		},
 		{
-			ID:        fmt.Sprintf("http://api.ft.com/things/%s", personBorisJohnsonUUID),
-			PrefLabel: "Boris Johnson",
+			Thing: Thing{
+				ID:        fmt.Sprintf("http://api.ft.com/things/%s", personBorisJohnsonUUID),
+				APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personBorisJohnsonUUID),
+				PrefLabel: "Boris Johnson",
+			},
+			Mentions:     2,
+			ContentCount: 2,
+			Score:        2,
+		},
+	}
+}
+
+func getExpectedTrendingPeople(mentions int, previousMentions int) []TrendingThing {
+	growth := float64(mentions - previousMentions)
+	if previousMentions > 0 {
+		growth = growth / float64(previousMentions)
+	}
+	return []TrendingThing{
+		{
+			Thing: Thing{
+				ID:        fmt.Sprintf("http://api.ft.com/things/%s", personSiobhanMordenUUID),
+				APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personSiobhanMordenUUID),
+				PrefLabel: "Siobhan Morden",
+			},
+			Mentions:         mentions,
+			PreviousMentions: previousMentions,
+			Delta:            mentions - previousMentions,
+			Growth:           growth,
+		},
+		{
+			Thing: Thing{
+				ID:        fmt.Sprintf("http://api.ft.com/things/%s", personBorisJohnsonUUID),
+				APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personBorisJohnsonUUID),
+				PrefLabel: "Boris Johnson",
+			},
+			Mentions:         mentions,
+			PreviousMentions: previousMentions,
+			Delta:            mentions - previousMentions,
+			Growth:           growth,
+		},
+	}
+}
+
+func getExpectedShortestPath() ConnectionPath {
+	boris := Thing{
+		ID:        fmt.Sprintf("http://api.ft.com/things/%s", personBorisJohnsonUUID),
+		APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personBorisJohnsonUUID),
+		PrefLabel: "Boris Johnson",
+	}
+	siobhan := Thing{
+		ID:        fmt.Sprintf("http://api.ft.com/things/%s", personSiobhanMordenUUID),
+		APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personSiobhanMordenUUID),
+		PrefLabel: "Siobhan Morden",
+	}
+	return ConnectionPath{
+		People: []Thing{boris, siobhan},
+		Hops: []PathHop{
+			{
+				From: boris,
+				To:   siobhan,
+				Content: []Content{
+					{
+						ID:     "3fc9fe3e-af8c-4f7f-961a-e5065392bb31",
+						APIURL: "http://api.ft.com/content/3fc9fe3e-af8c-4f7f-961a-e5065392bb31",
+						Title:  "Bitcoin story makes Newsweek the headline",
+					},
+					{
+						ID:     "a435b4ec-b207-4dce-ac0a-f8e7bbef310b",
+						APIURL: "http://api.ft.com/content/a435b4ec-b207-4dce-ac0a-f8e7bbef310b",
+						Title:  "Learn Golang",
+					},
+				},
+			},
+		},
+	}
+}
+
+func getExpectedNetwork() Network {
+	return Network{
+		Nodes: []NetworkNode{
+			{
+				Person: Thing{
+					ID:        fmt.Sprintf("http://api.ft.com/things/%s", personBorisJohnsonUUID),
+					APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personBorisJohnsonUUID),
+					PrefLabel: "Boris Johnson",
+				},
+				Distance: 0,
+				Mentions: 2,
+			},
+			{
+				Person: Thing{
+					ID:        fmt.Sprintf("http://api.ft.com/things/%s", personSiobhanMordenUUID),
+					APIURL:    fmt.Sprintf("http://api.ft.com/people/%s", personSiobhanMordenUUID),
+					PrefLabel: "Siobhan Morden",
+				},
+				Distance: 1,
+				Mentions: 2,
+			},
+		},
+		Edges: []NetworkEdge{
+			{
+				Source:  fmt.Sprintf("http://api.ft.com/things/%s", personSiobhanMordenUUID),
+				Target:  fmt.Sprintf("http://api.ft.com/things/%s", personBorisJohnsonUUID),
+				Count:   2,
+				Content: []string{contentUUID, content2UUID},
+			},
 		},
 	}
 }
@@ -261,6 +1141,16 @@ func writeJSONToAnnotationsService(service annotations.Service, contentUUID stri
 
 func getDatabaseConnection(t *testing.T) neoutils.NeoConnection {
 	url := os.Getenv("NEO4J_TEST_URL")
+	if os.Getenv("NEO4J_TEST_DRIVER") == "bolt" {
+		if url == "" {
+			url = "bolt://localhost:7687"
+		}
+		driver, err := ConnectBolt(url)
+		require.NoError(t, err, "Failed to connect to Neo4j")
+		// Fixtures are written through the same connection the tests read from
+		return NewBoltConnection(driver, neo4j.AccessModeWrite)
+	}
+
 	if url == "" {
 		url = "http://localhost:7474/db/data"
 	}
@@ -268,7 +1158,7 @@ func getDatabaseConnection(t *testing.T) neoutils.NeoConnection {
 	conf.Transactional = false
 	db, err := neoutils.Connect(url, conf)
 	require.NoError(t, err, "Failed to connect to Neo4j")
-	return db
+	return NewRESTConnection(db)
 }
 
 //DELETES ALL DATA! DO NOT USE IN PRODUCTION!!!
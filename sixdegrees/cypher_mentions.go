@@ -0,0 +1,115 @@
+package sixdegrees
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmcvetta/neoism"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+var mentionsIntervals = []string{"day", "week", "month"}
+
+type neoDailyMentionsReadStruct struct {
+	Day   int64 `json:"day"`
+	Count int   `json:"count"`
+}
+
+func (cd CypherDriver) Mentions(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, interval string) ([]MentionsBucket, bool, error) {
+	if !isKnownMentionsInterval(interval) {
+		return []MentionsBucket{}, false, fmt.Errorf("unknown interval %s", interval)
+	}
+
+	people := []neoThingReadStruct{}
+	personQuery := &neoism.CypherQuery{
+		Statement: `MATCH (p:Person{prefUUID:$uuid})
+					RETURN
+						p.prefUUID as uuid,
+						p.prefLabel as prefLabel`,
+		Parameters: neoism.Props{"uuid": uuid},
+		Result:     &people,
+	}
+
+	// Content is counted per UTC day, weeks and months are then made of whole days
+	days := []neoDailyMentionsReadStruct{}
+	mentionsQuery := &neoism.CypherQuery{
+		Statement: `MATCH (p:Person{prefUUID:$uuid})<-[:EQUIVALENT_TO]-(:Person)<-[:MENTIONS]-(c:Content)
+					WHERE
+						c.publishedDateEpoch > $fromDate
+						AND c.publishedDateEpoch < $toDate
+					WITH
+						c.publishedDateEpoch - c.publishedDateEpoch % $secondsPerDay as day,
+						c
+					RETURN
+						day,
+						count(DISTINCT c) as count
+					ORDER BY
+						day ASC`,
+		Parameters: neoism.Props{
+			"uuid":          uuid,
+			"fromDate":      fromDateEpoch,
+			"toDate":        toDateEpoch,
+			"secondsPerDay": secondsPerDay,
+		},
+		Result: &days,
+	}
+
+	err := cd.cypherBatch(ctx, []*neoism.CypherQuery{personQuery, mentionsQuery})
+	if err != nil || len(people) == 0 {
+		return []MentionsBucket{}, false, err
+	}
+
+	return bucketMentions(days, fromDateEpoch, toDateEpoch, interval), true, nil
+}
+
+func bucketMentions(days []neoDailyMentionsReadStruct, fromDateEpoch int64, toDateEpoch int64, interval string) []MentionsBucket {
+	buckets := []MentionsBucket{}
+	index := map[int64]int{}
+
+	to := time.Unix(toDateEpoch, 0).UTC()
+	for start := startOfBucket(time.Unix(fromDateEpoch, 0), interval); start.Before(to); start = nextBucket(start, interval) {
+		index[start.Unix()] = len(buckets)
+		buckets = append(buckets, MentionsBucket{Date: start.Format("2006-01-02")})
+	}
+
+	for _, day := range days {
+		if i, ok := index[startOfBucket(time.Unix(day.Day, 0), interval).Unix()]; ok {
+			buckets[i].Count += day.Count
+		}
+	}
+	return buckets
+}
+
+// Weeks start on Monday, as in ISO 8601
+func startOfBucket(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case "week":
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+func nextBucket(start time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func isKnownMentionsInterval(interval string) bool {
+	for _, known := range mentionsIntervals {
+		if interval == known {
+			return true
+		}
+	}
+	return false
+}
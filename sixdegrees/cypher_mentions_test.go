@@ -0,0 +1,36 @@
+package sixdegrees
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketMentions(t *testing.T) {
+	epoch := func(date string) int64 {
+		day, _ := time.Parse("2006-01-02", date)
+		return day.Unix()
+	}
+	days := []neoDailyMentionsReadStruct{
+		{Day: epoch("2016-12-30"), Count: 3},
+		{Day: epoch("2017-01-01"), Count: 1},
+		{Day: epoch("2017-01-02"), Count: 2},
+	}
+
+	assert.Equal(t, []MentionsBucket{
+		{Date: "2016-12-26", Count: 4},
+		{Date: "2017-01-02", Count: 2},
+		{Date: "2017-01-09", Count: 0},
+	}, bucketMentions(days, epoch("2016-12-29"), epoch("2017-01-10"), "week"), "Weeks should start on Monday")
+
+	assert.Equal(t, []MentionsBucket{
+		{Date: "2016-12-01", Count: 3},
+		{Date: "2017-01-01", Count: 3},
+	}, bucketMentions(days, epoch("2016-12-29"), epoch("2017-01-10"), "month"), "Months should start on their first day")
+
+	assert.Equal(t, []MentionsBucket{
+		{Date: "2016-12-31", Count: 0},
+		{Date: "2017-01-01", Count: 1},
+	}, bucketMentions(days, epoch("2016-12-31"), epoch("2017-01-01")+1, "day"), "Days outside of the period should be ignored")
+}
@@ -1,52 +1,74 @@
 package sixdegrees
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/Financial-Times/neo-model-utils-go/mapper"
 	"github.com/jmcvetta/neoism"
 )
 
-func (cd CypherDriver) MostMentioned(fromDateEpoch int64, toDateEpoch int64, limit int) ([]Thing, bool, error) {
+func (cd CypherDriver) MostMentioned(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, filter MentionFilter) ([]MentionedThing, bool, error) {
+	if err := validateConceptTypes(conceptType); err != nil {
+		return []MentionedThing{}, false, err
+	}
+	if err := validateMentionFilter(filter); err != nil {
+		return []MentionedThing{}, false, err
+	}
+
 	results := []neoMentionsReadStruct{}
 	query := &neoism.CypherQuery{
-		Statement: `MATCH (c:Content)-[a:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p:Person)
+		Statement: fmt.Sprintf(`MATCH (c:Content)-[a:%[3]s]->(:%[1]s)-[:EQUIVALENT_TO]->(p:%[1]s)
 					WHERE
-						c.publishedDateEpoch > {fromDateEpoch}
-						AND c.publishedDateEpoch < {toDateEpoch}
+						c.publishedDateEpoch > $fromDateEpoch
+						AND c.publishedDateEpoch < $toDateEpoch
+						AND coalesce(a.confidenceScore, 1.0) >= $minConfidence%[4]s
 					WITH
 						p.prefLabel as prefLabel,
 						p.prefUUID as uuid,
-						COUNT(a) as mentions
+						COUNT(a) as mentions,
+						COUNT(DISTINCT c) as contentCount,
+						SUM(%[2]s) as score%[5]s
 					RETURN
 						uuid,
 						prefLabel,
-						mentions
+						mentions,
+						contentCount,
+						score
 					ORDER BY
+						score DESC,
 						mentions DESC,
 						uuid ASC
-					LIMIT {mentionsLimit}`,
-		Parameters: neoism.Props{
+					LIMIT $limit`, conceptType, mentionWeight("a", filter), mentionRelationships(filter), contentFilter("c", filter), pageFilter("mentions", page)),
+		Parameters: pageParameters(neoism.Props{
 			"fromDateEpoch": fromDateEpoch,
 			"toDateEpoch":   toDateEpoch,
-			"mentionsLimit": limit,
-		},
+			"minConfidence": filter.MinConfidence,
+			"brands":        filter.Brands,
+			"contentTypes":  filter.ContentTypes,
+		}, page),
 		Result: &results,
 	}
 
-	err := cd.conn.CypherBatch([]*neoism.CypherQuery{query})
+	err := cd.cypherBatch(ctx, []*neoism.CypherQuery{query})
 	if err != nil || len(results) == 0 {
-		return []Thing{}, false, err
+		return []MentionedThing{}, false, err
 	}
 
-	return transformToMentionPeople(&results), true, nil
+	return transformToMentionedThings(&results, conceptType), true, nil
 }
 
-func transformToMentionPeople(neo *[]neoMentionsReadStruct) []Thing {
-	peopleList := []Thing{}
+func transformToMentionedThings(neo *[]neoMentionsReadStruct, conceptType string) []MentionedThing {
+	mentionedList := []MentionedThing{}
 	for _, neoCon := range *neo {
-		var thing = Thing{}
-		thing.ID = mapper.IDURL(neoCon.UUID)
-		thing.PrefLabel = neoCon.PrefLabel
-		peopleList = append(peopleList, thing)
+		var mentioned = MentionedThing{}
+		mentioned.ID = mapper.IDURL(neoCon.UUID)
+		mentioned.APIURL = mapper.APIURL(neoCon.UUID, []string{conceptType}, "local")
+		mentioned.PrefLabel = neoCon.PrefLabel
+		mentioned.Mentions = neoCon.Mentions
+		mentioned.ContentCount = neoCon.ContentCount
+		mentioned.Score = neoCon.Score
+		mentionedList = append(mentionedList, mentioned)
 	}
-	return peopleList
+	return mentionedList
 }
@@ -0,0 +1,214 @@
+package sixdegrees
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Financial-Times/neo-model-utils-go/mapper"
+	"github.com/jmcvetta/neoism"
+)
+
+type neoNetworkEdgeReadStruct struct {
+	Source  string   `json:"source"`
+	Target  string   `json:"target"`
+	Count   int      `json:"count"`
+	Content []string `json:"content"`
+}
+
+type neoPersonMentionsReadStruct struct {
+	UUID     string `json:"uuid"`
+	Mentions int    `json:"mentions"`
+}
+
+func (cd CypherDriver) Network(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, depth int, limit int, minimumConnections int) (Network, bool, error) {
+	root := []neoThingReadStruct{}
+	rootQuery := &neoism.CypherQuery{
+		Statement: `
+			MATCH (p:Person{prefUUID:$uuid})
+			RETURN
+				p.prefUUID as uuid,
+				p.prefLabel as prefLabel
+		`,
+		Parameters: neoism.Props{
+			"uuid": uuid,
+		},
+		Result: &root,
+	}
+
+	network := Network{
+		Nodes: []NetworkNode{},
+		Edges: []NetworkEdge{},
+	}
+	visited := []string{uuid}
+	frontier := []string{uuid}
+
+	for distance := 1; distance <= depth && len(frontier) > 0; distance++ {
+		ring := []neoThingReadStruct{}
+		queries := []*neoism.CypherQuery{networkRingQuery(frontier, visited, fromDateEpoch, toDateEpoch, limit, minimumConnections, &ring)}
+		if distance == 1 {
+			queries = append([]*neoism.CypherQuery{rootQuery}, queries...)
+		}
+
+		if err := cd.cypherBatch(ctx, queries); err != nil {
+			return Network{}, false, err
+		}
+		if distance == 1 {
+			if len(root) == 0 || len(ring) == 0 {
+				return Network{}, false, nil
+			}
+			network.Nodes = append(network.Nodes, transformToNetworkNode(root[0], 0))
+		}
+
+		frontier = []string{}
+		for _, neoPerson := range ring {
+			network.Nodes = append(network.Nodes, transformToNetworkNode(neoPerson, distance))
+			frontier = append(frontier, neoPerson.UUID)
+			visited = append(visited, neoPerson.UUID)
+		}
+	}
+
+	edges := []neoNetworkEdgeReadStruct{}
+	edgesQuery := coMentionEdgesQuery(visited, fromDateEpoch, toDateEpoch, minimumConnections, &edges)
+	mentions := []neoPersonMentionsReadStruct{}
+	mentionsQuery := personMentionsQuery(visited, fromDateEpoch, toDateEpoch, &mentions)
+
+	if err := cd.cypherBatch(ctx, []*neoism.CypherQuery{edgesQuery, mentionsQuery}); err != nil {
+		return Network{}, false, err
+	}
+
+	mentionsByUUID := map[string]int{}
+	for _, neoMentions := range mentions {
+		mentionsByUUID[neoMentions.UUID] = neoMentions.Mentions
+	}
+	// Nodes were added in the order their people were visited
+	for i, uuid := range visited {
+		network.Nodes[i].Mentions = mentionsByUUID[uuid]
+	}
+
+	network.Edges = transformToNetworkEdges(edges)
+	return network, true, nil
+}
+
+// How much content in the period mentions each of the given people
+func personMentionsQuery(uuids []string, fromDateEpoch int64, toDateEpoch int64, result *[]neoPersonMentionsReadStruct) *neoism.CypherQuery {
+	return &neoism.CypherQuery{
+		Statement: `
+			MATCH (c:Content)
+			WHERE
+				c.publishedDateEpoch < $toDate
+				AND c.publishedDateEpoch > $fromDate
+			MATCH (c)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p:Person)
+			WHERE p.prefUUID IN $uuids
+			RETURN
+				p.prefUUID as uuid,
+				count(distinct(c)) as mentions
+		`,
+		Parameters: neoism.Props{
+			"uuids":    uuids,
+			"fromDate": fromDateEpoch,
+			"toDate":   toDateEpoch,
+		},
+		Result: result,
+	}
+}
+
+// The edges between the given people, each pair once
+func coMentionEdgesQuery(uuids []string, fromDateEpoch int64, toDateEpoch int64, minimumConnections int, result *[]neoNetworkEdgeReadStruct) *neoism.CypherQuery {
+	return &neoism.CypherQuery{
+		Statement: `
+			MATCH (c:Content)
+			WHERE
+				c.publishedDateEpoch < $toDate
+				AND c.publishedDateEpoch > $fromDate
+			MATCH (p:Person)<-[:EQUIVALENT_TO]-(:Person)<-[:MENTIONS]-(c)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p2:Person)
+			WHERE
+				p.prefUUID IN $uuids
+				AND p2.prefUUID IN $uuids
+				AND p.prefUUID < p2.prefUUID
+			WITH
+				p,
+				p2,
+				count(distinct(c)) as cm,
+				collect(distinct(c.uuid)) as content
+			WHERE cm >= $minimumConnections
+			RETURN
+				p.prefUUID as source,
+				p2.prefUUID as target,
+				cm as count,
+				content
+			ORDER BY
+				count DESC,
+				source ASC,
+				target ASC
+		`,
+		Parameters: neoism.Props{
+			"uuids":              uuids,
+			"fromDate":           fromDateEpoch,
+			"toDate":             toDateEpoch,
+			"minimumConnections": minimumConnections,
+		},
+		Result: result,
+	}
+}
+
+func networkRingQuery(frontier []string, visited []string, fromDateEpoch int64, toDateEpoch int64, limit int, minimumConnections int, result *[]neoThingReadStruct) *neoism.CypherQuery {
+	return &neoism.CypherQuery{
+		Statement: `
+			MATCH (c:Content)
+			WHERE
+				c.publishedDateEpoch < $toDate
+				AND c.publishedDateEpoch > $fromDate
+			MATCH (p:Person)<-[:EQUIVALENT_TO]-(:Person)<-[:MENTIONS]-(c)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p2:Person)
+			WHERE
+				p.prefUUID IN $frontier
+				AND NOT p2.prefUUID IN $visited
+			WITH
+				p,
+				p2,
+				count(distinct(c)) as cm
+			WHERE cm >= $minimumConnections
+			WITH
+				p2.prefUUID as uuid,
+				p2.prefLabel as prefLabel,
+				sum(cm) as count
+			RETURN
+				uuid,
+				prefLabel
+			ORDER BY
+				count DESC,
+				uuid ASC
+			LIMIT $limit
+		`,
+		Parameters: neoism.Props{
+			"frontier":           frontier,
+			"visited":            visited,
+			"fromDate":           fromDateEpoch,
+			"toDate":             toDateEpoch,
+			"minimumConnections": minimumConnections,
+			"limit":              limit,
+		},
+		Result: result,
+	}
+}
+
+func transformToNetworkNode(neo neoThingReadStruct, distance int) NetworkNode {
+	return NetworkNode{
+		Person:   transformToPerson(neo),
+		Distance: distance,
+	}
+}
+
+func transformToNetworkEdges(neo []neoNetworkEdgeReadStruct) []NetworkEdge {
+	edges := []NetworkEdge{}
+	for _, neoEdge := range neo {
+		content := append([]string{}, neoEdge.Content...)
+		sort.Strings(content)
+		edges = append(edges, NetworkEdge{
+			Source:  mapper.IDURL(neoEdge.Source),
+			Target:  mapper.IDURL(neoEdge.Target),
+			Count:   neoEdge.Count,
+			Content: content,
+		})
+	}
+	return edges
+}
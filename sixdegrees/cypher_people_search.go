@@ -0,0 +1,165 @@
+package sixdegrees
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/Financial-Times/neo-model-utils-go/mapper"
+	"github.com/Financial-Times/neo-utils-go/neoutils"
+	"github.com/jmcvetta/neoism"
+)
+
+type neoMatchedThingReadStruct struct {
+	UUID      string `json:"uuid"`
+	PrefLabel string `json:"prefLabel"`
+	Match     string `json:"match"`
+	Mentions  int    `json:"mentions"`
+}
+
+// People are looked up in a full-text index of their labels and aliases, as matching them as strings reads every person
+const peopleSearchIndex = "peopleSearch"
+
+// Mentions are only counted for the candidates best matching the query, so a short one matching thousands of people stays
+// cheap. There are more candidates than the largest page for the most mentioned to still come first among them
+const peopleSearchCandidates = 200
+
+// EnsurePeopleSearchIndex creates the full-text index people are searched by unless it exists, and waits for it to be
+// online. Bolt is spoken to Neo4j 4.4 and later, which create it with Cypher, and REST to Neo4j 3.5 with a procedure
+func EnsurePeopleSearchIndex(conn neoutils.NeoConnection) error {
+	create := &neoism.CypherQuery{
+		Statement: `CREATE FULLTEXT INDEX ` + peopleSearchIndex + ` IF NOT EXISTS FOR (n:Person) ON EACH [n.prefLabel, n.aliases]`,
+	}
+	if _, bolt := conn.(*BoltConnection); !bolt {
+		existing := []struct {
+			Count int `json:"count"`
+		}{}
+		if err := conn.CypherBatch([]*neoism.CypherQuery{{
+			Statement:  `CALL db.indexes() YIELD indexName WHERE indexName = $index RETURN count(*) as count`,
+			Parameters: neoism.Props{"index": peopleSearchIndex},
+			Result:     &existing,
+		}}); err != nil {
+			return err
+		}
+		create = &neoism.CypherQuery{
+			Statement:  `CALL db.index.fulltext.createNodeIndex($index, ['Person'], ['prefLabel', 'aliases'])`,
+			Parameters: neoism.Props{"index": peopleSearchIndex},
+		}
+		if len(existing) > 0 && existing[0].Count > 0 {
+			create = nil
+		}
+	}
+
+	// Schema changes get a transaction of their own
+	if create != nil {
+		if err := conn.CypherBatch([]*neoism.CypherQuery{create}); err != nil {
+			return err
+		}
+	}
+	return conn.CypherBatch([]*neoism.CypherQuery{{Statement: `CALL db.awaitIndexes(300)`}})
+}
+
+// SearchPeople matches the start of the words of the labels and aliases of people, case insensitively.
+// People whose whole label or alias starts with the query come first, then the most mentioned in the period
+func (cd CypherDriver) SearchPeople(ctx context.Context, query string, fromDateEpoch int64, toDateEpoch int64, limit int) ([]MatchedThing, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	search := peopleSearchTerms(query)
+	if search == "" {
+		return []MatchedThing{}, nil
+	}
+
+	// The index finds people with words starting like those of the query, and the labels they match are then
+	// checked to start with the query as a whole
+	results := []neoMatchedThingReadStruct{}
+	cypherQuery := &neoism.CypherQuery{
+		Statement: `
+			CALL db.index.fulltext.queryNodes($index, $search) YIELD node, score
+			WITH node as p, score
+			WHERE p.prefUUID IS NOT NULL
+			WITH
+				p,
+				score,
+				[label IN [p.prefLabel] + coalesce(p.aliases, []) WHERE toLower(label) STARTS WITH $query OR toLower(label) CONTAINS $wordQuery] as matches
+			WHERE size(matches) > 0
+			WITH
+				p,
+				score,
+				[label IN matches WHERE toLower(label) STARTS WITH $query] as prefixed,
+				matches
+			ORDER BY
+				size(prefixed) > 0 DESC,
+				score DESC,
+				p.prefUUID ASC
+			LIMIT $candidates
+			OPTIONAL MATCH (p)<-[:EQUIVALENT_TO]-(:Person)<-[:MENTIONS]-(c:Content)
+			WHERE
+				c.publishedDateEpoch < $toDate
+				AND c.publishedDateEpoch > $fromDate
+			WITH
+				p,
+				size(prefixed) > 0 as isPrefix,
+				head(prefixed + matches) as match,
+				count(distinct(c)) as mentions
+			RETURN
+				p.prefUUID as uuid,
+				p.prefLabel as prefLabel,
+				match,
+				mentions
+			ORDER BY
+				isPrefix DESC,
+				mentions DESC,
+				prefLabel ASC,
+				uuid ASC
+			LIMIT $limit
+		`,
+		Parameters: neoism.Props{
+			"index":      peopleSearchIndex,
+			"search":     search,
+			"query":      query,
+			"wordQuery":  " " + query,
+			"fromDate":   fromDateEpoch,
+			"toDate":     toDateEpoch,
+			"candidates": peopleSearchCandidates,
+			"limit":      limit,
+		},
+		Result: &results,
+	}
+
+	if err := cd.cypherBatch(ctx, []*neoism.CypherQuery{cypherQuery}); err != nil {
+		return []MatchedThing{}, err
+	}
+
+	return transformToMatchedThings(results), nil
+}
+
+// peopleSearchTerms asks the full-text index for every word of the query as the start of a word. Only the first run of
+// letters and digits of each word is asked for, as the index may split words at punctuation or not, which also leaves
+// nothing Lucene would read as its own syntax
+func peopleSearchTerms(query string) string {
+	notAlphanumeric := func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}
+	terms := []string{}
+	for _, word := range strings.Fields(query) {
+		if parts := strings.FieldsFunc(word, notAlphanumeric); len(parts) > 0 {
+			terms = append(terms, parts[0]+"*")
+		}
+	}
+	return strings.Join(terms, " AND ")
+}
+
+func transformToMatchedThings(neo []neoMatchedThingReadStruct) []MatchedThing {
+	matched := []MatchedThing{}
+	for _, neoMatch := range neo {
+		matched = append(matched, MatchedThing{
+			Thing: Thing{
+				ID:        mapper.IDURL(neoMatch.UUID),
+				APIURL:    mapper.APIURL(neoMatch.UUID, []string{"Person"}, "local"),
+				PrefLabel: neoMatch.PrefLabel,
+			},
+			Match:    neoMatch.Match,
+			Mentions: neoMatch.Mentions,
+		})
+	}
+	return matched
+}
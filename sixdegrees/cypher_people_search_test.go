@@ -0,0 +1,15 @@
+package sixdegrees
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeopleSearchTerms(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("alexander* AND b*", peopleSearchTerms("alexander b"))
+	assert.Equal("o* AND b*", peopleSearchTerms("o'brien b"))
+	assert.Equal("smith*", peopleSearchTerms("smith-jo"))
+	assert.Equal("", peopleSearchTerms(`"(*)"`))
+}
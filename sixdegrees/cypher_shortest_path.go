@@ -0,0 +1,147 @@
+package sixdegrees
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Financial-Times/neo-model-utils-go/mapper"
+	"github.com/jmcvetta/neoism"
+)
+
+// Every hop between two people goes person <- source person <- content -> source person -> person
+const relationshipsPerHop = 4
+
+type neoThingReadStruct struct {
+	UUID      string `json:"uuid"`
+	PrefLabel string `json:"prefLabel"`
+}
+
+type neoPathHopReadStruct struct {
+	Hop         int                    `json:"hop"`
+	Source      neoThingReadStruct     `json:"source"`
+	Target      neoThingReadStruct     `json:"target"`
+	ContentList []neoContentReadStruct `json:"contentList"`
+}
+
+func (cd CypherDriver) ShortestPath(ctx context.Context, fromUUID string, toUUID string, fromDateEpoch int64, toDateEpoch int64, maxHops int, contentLimit int) (ConnectionPath, bool, error) {
+	results := []neoPathHopReadStruct{}
+
+	// The shortest path may walk through source nodes only, so every person on it is mapped back to its canonical node
+	// and consecutive duplicates are collapsed before looking up the content connecting each pair. Source nodes not
+	// equivalent to any canonical one are not walked through, as they could not be mapped back and would break the chain.
+	statement := fmt.Sprintf(`
+		MATCH (p1:Person{prefUUID:$fromUUID}), (p2:Person{prefUUID:$toUUID})
+		MATCH path = shortestPath((p1)-[:EQUIVALENT_TO|MENTIONS*..%d]-(p2))
+		WHERE ALL(n IN nodes(path) WHERE
+			(
+				n:Person
+				AND (n.prefUUID IS NOT NULL OR (n)-[:EQUIVALENT_TO]->(:Person))
+			)
+			OR (
+				n:Content
+				AND n.publishedDateEpoch > $fromDate
+				AND n.publishedDateEpoch < $toDate
+			)
+		)
+		WITH [n IN nodes(path) WHERE n:Person |
+			CASE WHEN n.prefUUID IS NOT NULL THEN n ELSE head([(n)-[:EQUIVALENT_TO]->(canonical) | canonical]) END
+		] as chain
+		WITH reduce(people = [], p IN chain |
+			CASE WHEN size(people) > 0 AND last(people) = p THEN people ELSE people + p END
+		) as people
+		UNWIND range(0, size(people) - 2) as hop
+		WITH
+			hop,
+			people[hop] as hopFrom,
+			people[hop + 1] as hopTo
+		MATCH (hopFrom)<-[:EQUIVALENT_TO]-(:Person)<-[:MENTIONS]-(c:Content)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(hopTo)
+		WHERE
+			c.publishedDateEpoch > $fromDate
+			AND c.publishedDateEpoch < $toDate
+		WITH DISTINCT
+			hop,
+			hopFrom,
+			hopTo,
+			c
+		ORDER BY
+			c.uuid ASC
+		WITH
+			hop,
+			hopFrom,
+			hopTo,
+			collect({
+				uuid: c.uuid,
+				prefLabel: c.prefLabel
+			})[0..$contentLimit] as content
+		RETURN
+			hop,
+			{uuid: hopFrom.prefUUID, prefLabel: hopFrom.prefLabel} as source,
+			{uuid: hopTo.prefUUID, prefLabel: hopTo.prefLabel} as target,
+			content as contentList
+		ORDER BY
+			hop ASC
+	`, maxHops*relationshipsPerHop)
+
+	query := &neoism.CypherQuery{
+		Statement: statement,
+		Parameters: neoism.Props{
+			"fromUUID":     fromUUID,
+			"toUUID":       toUUID,
+			"fromDate":     fromDateEpoch,
+			"toDate":       toDateEpoch,
+			"contentLimit": contentLimit,
+		},
+		Result: &results,
+	}
+
+	if err := cd.cypherBatch(ctx, []*neoism.CypherQuery{query}); err != nil || len(results) == 0 {
+		return ConnectionPath{}, false, err
+	}
+
+	path, err := transformToConnectionPath(fromUUID, toUUID, &results)
+	if err != nil {
+		return ConnectionPath{}, false, err
+	}
+	return path, true, nil
+}
+
+// transformToConnectionPath fails on any hop not following on from the one before, rather than answering with people
+// connected through a missing link
+func transformToConnectionPath(fromUUID string, toUUID string, neo *[]neoPathHopReadStruct) (ConnectionPath, error) {
+	path := ConnectionPath{
+		People: []Thing{},
+		Hops:   []PathHop{},
+	}
+	previous := fromUUID
+	for i, neoHop := range *neo {
+		if neoHop.Hop != i || neoHop.Source.UUID != previous {
+			return ConnectionPath{}, fmt.Errorf("path from %s to %s is broken at hop %d", fromUUID, toUUID, i)
+		}
+		previous = neoHop.Target.UUID
+
+		from := transformToPerson(neoHop.Source)
+		to := transformToPerson(neoHop.Target)
+		if i == 0 {
+			path.People = append(path.People, from)
+		}
+		path.People = append(path.People, to)
+
+		path.Hops = append(path.Hops, PathHop{
+			From:    from,
+			To:      to,
+			Content: transformToContentList(neoHop.ContentList),
+		})
+	}
+	if previous != toUUID {
+		return ConnectionPath{}, fmt.Errorf("path from %s to %s is broken at hop %d", fromUUID, toUUID, len(*neo))
+	}
+	return path, nil
+}
+
+func transformToPerson(neo neoThingReadStruct) Thing {
+	return Thing{
+		ID:        mapper.IDURL(neo.UUID),
+		APIURL:    mapper.APIURL(neo.UUID, []string{"Person"}, "local"),
+		PrefLabel: neo.PrefLabel,
+	}
+}
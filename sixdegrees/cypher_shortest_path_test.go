@@ -0,0 +1,53 @@
+package sixdegrees
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformToConnectionPath(t *testing.T) {
+	assert := assert.New(t)
+	person := func(uuid string) neoThingReadStruct {
+		return neoThingReadStruct{UUID: uuid, PrefLabel: "Person " + uuid}
+	}
+
+	path, err := transformToConnectionPath("1", "3", &[]neoPathHopReadStruct{
+		{Hop: 0, Source: person("1"), Target: person("2")},
+		{Hop: 1, Source: person("2"), Target: person("3")},
+	})
+	assert.NoError(err)
+	assert.Len(path.People, 3)
+	assert.Len(path.Hops, 2)
+	assert.Equal("Person 2", path.Hops[1].From.PrefLabel)
+
+	tests := []struct {
+		name string
+		hops []neoPathHopReadStruct
+	}{
+		{
+			name: "MissingHop",
+			hops: []neoPathHopReadStruct{
+				{Hop: 0, Source: person("1"), Target: person("2")},
+				{Hop: 2, Source: person("4"), Target: person("3")},
+			},
+		},
+		{
+			name: "DroppedPerson",
+			hops: []neoPathHopReadStruct{
+				{Hop: 0, Source: person("1"), Target: person("2")},
+				{Hop: 1, Source: person("4"), Target: person("3")},
+			},
+		},
+		{
+			name: "MissingLastHop",
+			hops: []neoPathHopReadStruct{
+				{Hop: 0, Source: person("1"), Target: person("2")},
+			},
+		},
+	}
+	for _, test := range tests {
+		_, err := transformToConnectionPath("1", "3", &test.hops)
+		assert.Error(err, test.name)
+	}
+}
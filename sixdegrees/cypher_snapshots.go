@@ -0,0 +1,235 @@
+package sixdegrees
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmcvetta/neoism"
+)
+
+// Weightings of summed mentions, as the properties of daily summaries adding them up
+var snapshotWeightings = map[string]string{
+	"count":      "mentions",
+	"relevance":  "relevance",
+	"confidence": "confidence",
+}
+
+type neoSnapshotDayReadStruct struct {
+	Day int64 `json:"day"`
+}
+
+// snapshotWindow finds the days of a window to sum from snapshots, and the ranges of it to query live
+func (cd CypherDriver) snapshotWindow(ctx context.Context, fromDateEpoch int64, toDateEpoch int64) ([]int64, [][]int64, error) {
+	results := []neoSnapshotDayReadStruct{}
+	query := &neoism.CypherQuery{
+		Statement: `
+			MATCH (s:MentionSnapshot)
+			WHERE
+				s.day > $fromDate
+				AND s.day <= $lastDay
+			RETURN DISTINCT s.day as day
+			ORDER BY day
+		`,
+		Parameters: neoism.Props{
+			"fromDate": fromDateEpoch,
+			"lastDay":  toDateEpoch - secondsPerDay,
+		},
+		Result: &results,
+	}
+
+	if err := cd.cypherBatch(ctx, []*neoism.CypherQuery{query}); err != nil {
+		return nil, nil, err
+	}
+	built := []int64{}
+	for _, result := range results {
+		built = append(built, result.Day)
+	}
+	days, live := splitSnapshotWindow(fromDateEpoch, toDateEpoch, built)
+	return days, live, nil
+}
+
+// Every part of a window is either its summed days, or a range of it to query live. Each row of a part only matches one
+// kind of them, so the summaries and the live mentions add up in a single aggregation
+func snapshotParts(live [][]int64) []interface{} {
+	parts := []interface{}{map[string]interface{}{"daily": true, "from": 0, "to": 0}}
+	for _, rng := range live {
+		parts = append(parts, map[string]interface{}{"daily": false, "from": rng[0], "to": rng[1]})
+	}
+	return parts
+}
+
+func (cd CypherDriver) mostMentionedFromSnapshots(ctx context.Context, conceptType string, days []int64, live [][]int64, page PageRequest, filter MentionFilter) ([]MentionedThing, bool, error) {
+	if err := validateConceptTypes(conceptType); err != nil {
+		return []MentionedThing{}, false, err
+	}
+	if err := validateMentionFilter(filter); err != nil {
+		return []MentionedThing{}, false, err
+	}
+
+	results := []neoMentionsReadStruct{}
+	query := &neoism.CypherQuery{
+		Statement: fmt.Sprintf(`UNWIND $parts as part
+					OPTIONAL MATCH (s:DailyMentions)
+					WHERE
+						part.daily
+						AND s.day IN $days
+						AND s.conceptType = $conceptType
+					OPTIONAL MATCH (c:Content)
+					WHERE
+						NOT part.daily
+						AND c.publishedDateEpoch > part.from
+						AND c.publishedDateEpoch < part.to
+					OPTIONAL MATCH (c)-[a:MENTIONS]->(:%[1]s)-[:EQUIVALENT_TO]->(p:%[1]s)
+					WITH
+						coalesce(s.uuid, p.prefUUID) as uuid,
+						SUM(s.mentions) + COUNT(a) as mentions,
+						SUM(s.contentCount) + COUNT(DISTINCT c) as contentCount,
+						SUM(s.%[2]s) + SUM(CASE WHEN a IS NULL THEN 0.0 ELSE %[3]s END) as score
+					WHERE uuid IS NOT NULL
+					WITH
+						uuid,
+						mentions,
+						contentCount,
+						score%[4]s
+					ORDER BY
+						score DESC,
+						mentions DESC,
+						uuid ASC
+					LIMIT $limit
+					MATCH (p:%[1]s {prefUUID: uuid})
+					RETURN
+						uuid,
+						p.prefLabel as prefLabel,
+						mentions,
+						contentCount,
+						score
+					ORDER BY
+						score DESC,
+						mentions DESC,
+						uuid ASC`, conceptType, snapshotWeightings[filter.Weighting], mentionWeight("a", filter), pageFilter("mentions", page)),
+		Parameters: pageParameters(neoism.Props{
+			"parts":       snapshotParts(live),
+			"days":        days,
+			"conceptType": conceptType,
+		}, page),
+		Result: &results,
+	}
+
+	err := cd.cypherBatch(ctx, []*neoism.CypherQuery{query})
+	if err != nil || len(results) == 0 {
+		return []MentionedThing{}, false, err
+	}
+
+	return transformToMentionedThings(&results, conceptType), true, nil
+}
+
+func (cd CypherDriver) coMentionGraphFromSnapshots(ctx context.Context, days []int64, live [][]int64, maxNodes int, minimumConnections int) (CoMentionGraph, bool, error) {
+	people := []neoThingReadStruct{}
+	peopleQuery := &neoism.CypherQuery{
+		Statement: `
+			UNWIND $parts as part
+			OPTIONAL MATCH (s:DailyMentions)
+			WHERE
+				part.daily
+				AND s.day IN $days
+				AND s.conceptType = 'Person'
+			OPTIONAL MATCH (c:Content)
+			WHERE
+				NOT part.daily
+				AND c.publishedDateEpoch > part.from
+				AND c.publishedDateEpoch < part.to
+			OPTIONAL MATCH (c)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p:Person)
+			WITH
+				coalesce(s.uuid, p.prefUUID) as uuid,
+				sum(s.contentCount) + count(distinct(c)) as mentions
+			WHERE uuid IS NOT NULL
+			WITH
+				uuid,
+				mentions
+			ORDER BY
+				mentions DESC,
+				uuid ASC
+			LIMIT $maxNodes
+			MATCH (p:Person {prefUUID: uuid})
+			RETURN
+				uuid,
+				p.prefLabel as prefLabel
+			ORDER BY
+				mentions DESC,
+				uuid ASC
+		`,
+		Parameters: neoism.Props{
+			"parts":    snapshotParts(live),
+			"days":     days,
+			"maxNodes": maxNodes,
+		},
+		Result: &people,
+	}
+
+	if err := cd.cypherBatch(ctx, []*neoism.CypherQuery{peopleQuery}); err != nil || len(people) == 0 {
+		return CoMentionGraph{}, false, err
+	}
+
+	graph := CoMentionGraph{People: []Thing{}}
+	uuids := []string{}
+	for _, neoPerson := range people {
+		graph.People = append(graph.People, transformToPerson(neoPerson))
+		uuids = append(uuids, neoPerson.UUID)
+	}
+
+	edges := []neoNetworkEdgeReadStruct{}
+	edgesQuery := &neoism.CypherQuery{
+		Statement: `
+			UNWIND $parts as part
+			OPTIONAL MATCH (s:DailyCoMentions)
+			WHERE
+				part.daily
+				AND s.day IN $days
+				AND s.uuid IN $uuids
+				AND s.otherUUID IN $uuids
+			OPTIONAL MATCH (c:Content)
+			WHERE
+				NOT part.daily
+				AND c.publishedDateEpoch > part.from
+				AND c.publishedDateEpoch < part.to
+			OPTIONAL MATCH (p:Person)<-[:EQUIVALENT_TO]-(:Person)<-[:MENTIONS]-(c)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p2:Person)
+			WHERE
+				p.prefUUID IN $uuids
+				AND p2.prefUUID IN $uuids
+				AND p.prefUUID < p2.prefUUID
+			WITH
+				coalesce(s.uuid, p.prefUUID) as source,
+				coalesce(s.otherUUID, p2.prefUUID) as target,
+				reduce(content = [], daily IN collect(s.content) | content + daily) + collect(distinct(c.uuid)) as content
+			WHERE source IS NOT NULL
+			WITH
+				source,
+				target,
+				size(content) as cm,
+				content
+			WHERE cm >= $minimumConnections
+			RETURN
+				source,
+				target,
+				cm as count,
+				content
+			ORDER BY
+				count DESC,
+				source ASC,
+				target ASC
+		`,
+		Parameters: neoism.Props{
+			"parts":              snapshotParts(live),
+			"days":               days,
+			"uuids":              uuids,
+			"minimumConnections": minimumConnections,
+		},
+		Result: &edges,
+	}
+	if err := cd.cypherBatch(ctx, []*neoism.CypherQuery{edgesQuery}); err != nil {
+		return CoMentionGraph{}, false, err
+	}
+
+	graph.Edges = transformToNetworkEdges(edges)
+	return graph, true, nil
+}
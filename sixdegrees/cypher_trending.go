@@ -0,0 +1,98 @@
+package sixdegrees
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Financial-Times/neo-model-utils-go/mapper"
+	"github.com/jmcvetta/neoism"
+)
+
+var trendingRankings = map[string]string{
+	"absolute": "delta",
+	"relative": "growth",
+}
+
+type neoTrendingReadStruct struct {
+	UUID             string  `json:"uuid"`
+	PrefLabel        string  `json:"prefLabel"`
+	Mentions         int     `json:"mentions"`
+	PreviousMentions int     `json:"previousMentions"`
+	Delta            int     `json:"delta"`
+	Growth           float64 `json:"growth"`
+}
+
+func (cd CypherDriver) Trending(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, previousFromDateEpoch int64, previousToDateEpoch int64, limit int, rankBy string) ([]TrendingThing, bool, error) {
+	if err := validateConceptTypes(conceptType); err != nil {
+		return []TrendingThing{}, false, err
+	}
+	orderBy, ok := trendingRankings[rankBy]
+	if !ok {
+		return []TrendingThing{}, false, fmt.Errorf("unknown ranking %s", rankBy)
+	}
+
+	results := []neoTrendingReadStruct{}
+
+	// Growth is relative to at least one previous mention, so newcomers are not ranked as infinitely trending
+	query := &neoism.CypherQuery{
+		Statement: fmt.Sprintf(`MATCH (c:Content)-[a:MENTIONS]->(:%[1]s)-[:EQUIVALENT_TO]->(p:%[1]s)
+					WHERE
+						(c.publishedDateEpoch > $fromDateEpoch AND c.publishedDateEpoch < $toDateEpoch)
+						OR (c.publishedDateEpoch > $previousFromDateEpoch AND c.publishedDateEpoch < $previousToDateEpoch)
+					WITH
+						p,
+						sum(CASE WHEN c.publishedDateEpoch > $fromDateEpoch AND c.publishedDateEpoch < $toDateEpoch THEN 1 ELSE 0 END) as mentions,
+						sum(CASE WHEN c.publishedDateEpoch > $previousFromDateEpoch AND c.publishedDateEpoch < $previousToDateEpoch THEN 1 ELSE 0 END) as previousMentions
+					WHERE mentions > 0
+					WITH
+						p.prefLabel as prefLabel,
+						p.prefUUID as uuid,
+						mentions,
+						previousMentions,
+						mentions - previousMentions as delta,
+						toFloat(mentions - previousMentions) / CASE WHEN previousMentions > 0 THEN previousMentions ELSE 1 END as growth
+					RETURN
+						uuid,
+						prefLabel,
+						mentions,
+						previousMentions,
+						delta,
+						growth
+					ORDER BY
+						%[2]s DESC,
+						mentions DESC,
+						uuid ASC
+					LIMIT $mentionsLimit`, conceptType, orderBy),
+		Parameters: neoism.Props{
+			"fromDateEpoch":         fromDateEpoch,
+			"toDateEpoch":           toDateEpoch,
+			"previousFromDateEpoch": previousFromDateEpoch,
+			"previousToDateEpoch":   previousToDateEpoch,
+			"mentionsLimit":         limit,
+		},
+		Result: &results,
+	}
+
+	err := cd.cypherBatch(ctx, []*neoism.CypherQuery{query})
+	if err != nil || len(results) == 0 {
+		return []TrendingThing{}, false, err
+	}
+
+	return transformToTrendingThings(&results, conceptType), true, nil
+}
+
+func transformToTrendingThings(neo *[]neoTrendingReadStruct, conceptType string) []TrendingThing {
+	trendingList := []TrendingThing{}
+	for _, neoTrending := range *neo {
+		var trending = TrendingThing{}
+		trending.ID = mapper.IDURL(neoTrending.UUID)
+		trending.APIURL = mapper.APIURL(neoTrending.UUID, []string{conceptType}, "local")
+		trending.PrefLabel = neoTrending.PrefLabel
+		trending.Mentions = neoTrending.Mentions
+		trending.PreviousMentions = neoTrending.PreviousMentions
+		trending.Delta = neoTrending.Delta
+		trending.Growth = neoTrending.Growth
+		trendingList = append(trendingList, trending)
+	}
+	return trendingList
+}
@@ -1,12 +1,16 @@
 package sixdegrees
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	fthealth "github.com/Financial-Times/go-fthealth/v1_1"
 	logger "github.com/Financial-Times/go-logger"
@@ -23,20 +27,47 @@ const (
 	defaultMostMentionedPeopleResultLimit = 20
 	defaultMinConnections                 = 5
 	defaultContentLimit                   = 3
+	defaultContentSort                    = ""
+	defaultContentSortV2                  = "newest"
+	defaultConceptType                    = "Person"
+	defaultTrendingRanking                = "absolute"
+	defaultMentionsInterval               = "day"
+	defaultWeighting                      = "count"
+	defaultPredicate                      = "mentions"
+	defaultCommunitiesMaxNodes            = 100
+	maxCommunitiesMaxNodes                = 500
+	defaultCentralityMetric               = "pagerank"
+	defaultCentralPeopleResultLimit       = 20
+	defaultCentralityMaxNodes             = 100
+	maxCentralityMaxNodes                 = 500
+	defaultPeopleSearchResultLimit        = 10
+	maxPeopleSearchResultLimit            = 50
+	minPeopleSearchQueryLength            = 3
+	defaultConnectionResultLimit          = 20
+	maxResultLimit                        = 1000
+	defaultMaxHops                        = 6
+	defaultNetworkDepth                   = 2
+	maxNetworkDepth                       = 3
+	maxNetworkResultLimit                 = 100
+	staleResultsCheckPeriod               = 5 * time.Minute
 )
 
 type defaultTimeGetter func() time.Time
 
-func NewHandler(driver Driver, cacheControlHeader string) *Handler {
+func NewHandler(driver Driver, cacheControlHeader string, cursorSecret []byte, timeouts QueryTimeouts) *Handler {
 	return &Handler{
 		driver:             driver,
 		cacheControlHeader: cacheControlHeader,
+		cursors:            cursorSigner{secret: cursorSecret},
+		timeouts:           timeouts,
 	}
 }
 
 type Handler struct {
 	driver             Driver
 	cacheControlHeader string
+	cursors            cursorSigner
+	timeouts           QueryTimeouts
 }
 
 func (hh *Handler) RegisterAdminHandlers(router *mux.Router, appSystemCode string, appName string, appDescription string, enableRequestLogging bool) http.Handler {
@@ -47,6 +78,7 @@ func (hh *Handler) RegisterAdminHandlers(router *mux.Router, appSystemCode strin
 			Description: appDescription,
 			Checks: []fthealth.Check{
 				hh.HealthCheck(),
+				hh.StaleResultsCheck(),
 			},
 		},
 		Timeout: 10 * time.Second,
@@ -67,7 +99,21 @@ func (hh *Handler) RegisterAdminHandlers(router *mux.Router, appSystemCode strin
 
 func (hh *Handler) RegisterHandlers(router *mux.Router) http.Handler {
 	router.HandleFunc("/sixdegrees/connectedPeople", hh.GetConnectedPeople).Methods("GET")
+	router.HandleFunc("/sixdegrees/v2/connectedPeople", hh.GetConnectedPeopleV2).Methods("GET")
+	router.HandleFunc("/sixdegrees/connectedPeople:batch", hh.GetConnectedPeopleBatch).Methods("POST")
 	router.HandleFunc("/sixdegrees/mostMentionedPeople", hh.GetMostMentionedPeople).Methods("GET")
+	router.HandleFunc("/sixdegrees/v2/mostMentionedPeople", hh.GetMostMentionedPeopleV2).Methods("GET")
+	router.HandleFunc("/sixdegrees/connectedConcepts", hh.GetConnectedConcepts).Methods("GET")
+	router.HandleFunc("/sixdegrees/v2/connectedConcepts", hh.GetConnectedConceptsV2).Methods("GET")
+	router.HandleFunc("/sixdegrees/mostMentionedConcepts", hh.GetMostMentionedConcepts).Methods("GET")
+	router.HandleFunc("/sixdegrees/trendingPeople", hh.GetTrendingPeople).Methods("GET")
+	router.HandleFunc("/sixdegrees/people/search", hh.GetPeopleSearch).Methods("GET")
+	router.HandleFunc("/sixdegrees/people/{uuid}/mentions", hh.GetMentions).Methods("GET")
+	router.HandleFunc("/sixdegrees/connection", hh.GetConnection).Methods("GET")
+	router.HandleFunc("/sixdegrees/path", hh.GetShortestPath).Methods("GET")
+	router.HandleFunc("/sixdegrees/network", hh.GetNetwork).Methods("GET")
+	router.HandleFunc("/sixdegrees/communities", hh.GetCommunities).Methods("GET")
+	router.HandleFunc("/sixdegrees/centralPeople", hh.GetCentralPeople).Methods("GET")
 
 	var monitoringRouter http.Handler = router
 	monitoringRouter = httphandlers.TransactionAwareRequestLoggingHandler(logger.Logger(), monitoringRouter)
@@ -88,13 +134,46 @@ func (hh *Handler) HealthCheck() fthealth.Check {
 }
 
 func (hh *Handler) Checker() (string, error) {
-	err := hh.driver.CheckConnectivity()
+	err := hh.driver.CheckConnectivity(context.Background())
 	if err == nil {
 		return "Connectivity to neo4j is ok", err
 	}
 	return "Error connecting to neo4j", err
 }
 
+// staleResultsDriver is a driver answering failed queries with the last good results
+type staleResultsDriver interface {
+	StaleResults() (maxStale time.Duration, lastServed time.Time, lastAge time.Duration)
+}
+
+func (hh *Handler) StaleResultsCheck() fthealth.Check {
+	return fthealth.Check{
+		BusinessImpact:   "Public Six Degrees shows results older than usual",
+		Name:             "Check stale results - served while Neo4j fails, up to max-stale old",
+		PanicGuide:       "https://dewey.ft.com/public-six-degrees-api.html",
+		Severity:         2,
+		TechnicalSummary: `Queries to Neo4j failed lately, and were answered with older results. If this check fails, check the connectivity to Neo4j.`,
+		Checker:          hh.StaleResultsChecker,
+	}
+}
+
+// StaleResultsChecker fails while stale results were served lately, telling how old they may be and were
+func (hh *Handler) StaleResultsChecker() (string, error) {
+	driver, ok := hh.driver.(staleResultsDriver)
+	if !ok {
+		return "Stale results are not served", nil
+	}
+	maxStale, lastServed, lastAge := driver.StaleResults()
+	if maxStale <= 0 {
+		return "Stale results are not served", nil
+	}
+	if !lastServed.IsZero() && time.Since(lastServed) < staleResultsCheckPeriod {
+		msg := fmt.Sprintf("Serving results up to %v old while neo4j fails, the last one %v old", maxStale, lastAge.Truncate(time.Second))
+		return msg, errors.New(msg)
+	}
+	return fmt.Sprintf("Serving results up to %v old if neo4j fails, none served lately", maxStale), nil
+}
+
 func (hh *Handler) GTG() gtg.Status {
 	statusCheck := func() gtg.Status {
 		return gtgCheck(hh.Checker)
@@ -111,13 +190,168 @@ func gtgCheck(handler func() (string, error)) gtg.Status {
 }
 
 func (hh *Handler) GetMostMentionedPeople(w http.ResponseWriter, r *http.Request) {
+	hh.getMostMentioned(w, r, defaultConceptType, func(people []MentionedThing) interface{} {
+		things := []Thing{}
+		for _, person := range people {
+			things = append(things, person.Thing)
+		}
+		return things
+	})
+}
+
+func (hh *Handler) GetMostMentionedPeopleV2(w http.ResponseWriter, r *http.Request) {
+	hh.getMostMentioned(w, r, defaultConceptType, func(people []MentionedThing) interface{} {
+		return people
+	})
+}
+
+func (hh *Handler) GetMostMentionedConcepts(w http.ResponseWriter, r *http.Request) {
+	hh.getMostMentioned(w, r, "", func(concepts []MentionedThing) interface{} {
+		return concepts
+	})
+}
+
+func (hh *Handler) getMostMentioned(w http.ResponseWriter, r *http.Request, defaultType string, toResponse func([]MentionedThing) interface{}) {
+	resultLimitParam := r.URL.Query().Get("limit")
+	fromDateParam := r.URL.Query().Get("fromDate")
+	toDateParam := r.URL.Query().Get("toDate")
+	conceptTypeParam := r.URL.Query().Get("type")
+	predicatesParam := r.URL.Query().Get("predicates")
+	brandParam := r.URL.Query().Get("brand")
+	contentTypeParam := r.URL.Query().Get("contentType")
+	weightingParam := r.URL.Query().Get("weighting")
+	minConfidenceParam := r.URL.Query().Get("minConfidence")
+	cursorParam := r.URL.Query().Get("cursor")
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	conceptType, err := getConceptType(conceptTypeParam, defaultType)
+	if err != nil {
+		logger.WithError(err).Error("could not get concept type")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting type query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	limit, err := getResultLimit(resultLimitParam, defaultMostMentionedPeopleResultLimit)
+	if err != nil {
+		logger.WithError(err).Error("could not get limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting limit query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	fromDate, toDate, err := getDateTimePeriod(fromDateParam, toDateParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get period")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting toDate or fromDate query params: fromDate=%s, toDate=%s", fromDateParam, toDateParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	filter, err := getMentionFilter(weightingParam, minConfidenceParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get mention filter")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting weighting or minConfidence query params: weighting=%s, minConfidence=%s", weightingParam, minConfidenceParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	filter.Predicates, err = getPredicates(predicatesParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get predicates")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting predicates query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+	filter.Brands = splitListParam(brandParam)
+	filter.ContentTypes = splitListParam(contentTypeParam)
+
+	query := cursorQuery{
+		Path:          r.URL.Path,
+		ConceptType:   conceptType,
+		FromDateEpoch: fromDate.Unix(),
+		ToDateEpoch:   toDate.Unix(),
+		Filter:        filter,
+	}
+	page, err := hh.getPage(cursorParam, limit, &query)
+	if err != nil {
+		logger.WithError(err).Error("could not get page")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting cursor query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, r, "mostMentioned")
+	defer cancel()
+	mentioned, found, err := hh.driver.MostMentioned(ctx, query.ConceptType, query.FromDateEpoch, query.ToDateEpoch, page, query.Filter)
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve most mentioned concepts")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{"Error retrieving result from DB"})
+		w.Write([]byte(msg))
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		msg, _ := json.Marshal(ErrorMessage{"No result"})
+		w.Write([]byte(msg))
+		return
+	}
+
+	if len(mentioned) > page.Limit {
+		mentioned = mentioned[:page.Limit]
+		last := mentioned[len(mentioned)-1]
+		hh.setNextLink(w, query, page, PageKey{Score: last.Score, Count: last.Mentions, UUID: uuidOf(last.ID)})
+	}
+
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.WriteHeader(http.StatusOK)
+
+	if err = json.NewEncoder(w).Encode(toResponse(mentioned)); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (hh *Handler) GetTrendingPeople(w http.ResponseWriter, r *http.Request) {
 	resultLimitParam := r.URL.Query().Get("limit")
 	fromDateParam := r.URL.Query().Get("fromDate")
 	toDateParam := r.URL.Query().Get("toDate")
+	conceptTypeParam := r.URL.Query().Get("type")
+	rankByParam := r.URL.Query().Get("rankBy")
 
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 
-	limit, err := getLimit(resultLimitParam, defaultMostMentionedPeopleResultLimit)
+	conceptType, err := getConceptType(conceptTypeParam, defaultConceptType)
+	if err != nil {
+		logger.WithError(err).Error("could not get concept type")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting type query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	rankBy := defaultTrendingRanking
+	if rankByParam != "" {
+		rankBy = rankByParam
+	}
+	if _, ok := trendingRankings[rankBy]; !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting rankBy query param, must be absolute or relative: rankBy=%s", rankByParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	limit, err := getResultLimit(resultLimitParam, defaultMostMentionedPeopleResultLimit)
 	if err != nil {
 		logger.WithError(err).Error("could not get limit")
 		w.WriteHeader(http.StatusBadRequest)
@@ -134,10 +368,16 @@ func (hh *Handler) GetMostMentionedPeople(w http.ResponseWriter, r *http.Request
 		w.Write([]byte(msg))
 		return
 	}
+	previousFromDate, previousToDate := getPreviousDateTimePeriod(fromDate, toDate)
 
-	people, found, err := hh.driver.MostMentioned(fromDate.Unix(), toDate.Unix(), limit)
+	ctx, cancel := hh.queryContext(w, r, "trendingPeople")
+	defer cancel()
+	trending, found, err := hh.driver.Trending(ctx, conceptType, fromDate.Unix(), toDate.Unix(), previousFromDate.Unix(), previousToDate.Unix(), limit, rankBy)
+	if writeTimeout(w, err) {
+		return
+	}
 	if err != nil {
-		logger.WithError(err).Error("could not retrieve most mentioned people")
+		logger.WithError(err).Error("could not retrieve trending concepts")
 		w.WriteHeader(http.StatusInternalServerError)
 		msg, _ := json.Marshal(ErrorMessage{"Error retrieving result from DB"})
 		w.Write([]byte(msg))
@@ -153,12 +393,22 @@ func (hh *Handler) GetMostMentionedPeople(w http.ResponseWriter, r *http.Request
 	w.Header().Set("Cache-Control", hh.cacheControlHeader)
 	w.WriteHeader(http.StatusOK)
 
-	if err = json.NewEncoder(w).Encode(people); err != nil {
+	if err = json.NewEncoder(w).Encode(trending); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 }
 
+// GetConnectedPeople only scores connections when asked for a weighting, answering as it always has otherwise
 func (hh *Handler) GetConnectedPeople(w http.ResponseWriter, request *http.Request) {
+	hh.getConnectedPeople(w, request, defaultContentSort, false)
+}
+
+// GetConnectedPeopleV2 attaches the newest content to each connection by default
+func (hh *Handler) GetConnectedPeopleV2(w http.ResponseWriter, request *http.Request) {
+	hh.getConnectedPeople(w, request, defaultContentSortV2, true)
+}
+
+func (hh *Handler) getConnectedPeople(w http.ResponseWriter, request *http.Request, defaultSort string, alwaysScored bool) {
 	m, _ := url.ParseQuery(request.URL.RawQuery)
 
 	minimumConnectionsParam := m.Get("minimumConnections")
@@ -166,6 +416,14 @@ func (hh *Handler) GetConnectedPeople(w http.ResponseWriter, request *http.Reque
 	fromDateParam := m.Get("fromDate")
 	toDateParam := m.Get("toDate")
 	contentLimitParam := m.Get("contentLimit")
+	contentSortParam := m.Get("contentSort")
+	predicatesParam := m.Get("predicates")
+	brandParam := m.Get("brand")
+	contentTypeParam := m.Get("contentType")
+	weightingParam := m.Get("weighting")
+	minConfidenceParam := m.Get("minConfidence")
+	includeParam := m.Get("include")
+	cursorParam := m.Get("cursor")
 	uuid := m.Get("uuid")
 
 	logger := logger.WithField("uuid", uuid)
@@ -190,7 +448,7 @@ func (hh *Handler) GetConnectedPeople(w http.ResponseWriter, request *http.Reque
 		return
 	}
 
-	resultLimit, err := getLimit(resultLimitParam, defaultConnectedPeopleResultLimit)
+	resultLimit, err := getResultLimit(resultLimitParam, defaultConnectedPeopleResultLimit)
 	if err != nil {
 		logger.WithError(err).Error("could not get result limit")
 		w.WriteHeader(http.StatusBadRequest)
@@ -208,66 +466,1055 @@ func (hh *Handler) GetConnectedPeople(w http.ResponseWriter, request *http.Reque
 		return
 	}
 
-	connectedPeople, found, err := hh.driver.ConnectedPeople(uuid, fromDate.Unix(), toDate.Unix(), resultLimit, minimumConnections, contentLimit)
+	contentSort, err := getContentSort(contentSortParam, defaultSort)
 	if err != nil {
-		logger.WithError(err).Error("could not retrieve connected people")
-		w.WriteHeader(http.StatusInternalServerError)
-		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error retrieving result for %s, err=%v", uuid, err)})
+		logger.WithError(err).Error("could not get content sort")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting contentSort query param, err=%v", err)})
 		w.Write([]byte(msg))
 		return
 	}
 
-	if !found {
+	filter, err := getMentionFilter(weightingParam, minConfidenceParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get mention filter")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting weighting or minConfidence query params: weighting=%s, minConfidence=%s", weightingParam, minConfidenceParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	filter.Predicates, err = getPredicates(predicatesParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get predicates")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting predicates query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+	filter.Brands = splitListParam(brandParam)
+	filter.ContentTypes = splitListParam(contentTypeParam)
+
+	includes, err := getContentIncludes(includeParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get content includes")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting include query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	query := cursorQuery{
+		Path:               request.URL.Path,
+		UUID:               uuid,
+		FromDateEpoch:      fromDate.Unix(),
+		ToDateEpoch:        toDate.Unix(),
+		MinimumConnections: minimumConnections,
+		ContentLimit:       contentLimit,
+		ContentSort:        contentSort,
+		Filter:             filter,
+		Scored:             alwaysScored || weightingParam != "",
+		Include:            includes,
+	}
+	page, err := hh.getPage(cursorParam, resultLimit, &query)
+	if err != nil {
+		logger.WithError(err).Error("could not get page")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting cursor query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, request, "connectedPeople")
+	defer cancel()
+	connectedPeople, err := hh.driver.ConnectedPeople(ctx, query.UUID, query.FromDateEpoch, query.ToDateEpoch, page, query.MinimumConnections, query.ContentLimit, query.ContentSort, query.Filter)
+	if err == ErrConceptNotFound {
+		if hh.redirectToCanonical(ctx, w, request, query.UUID) {
+			return
+		}
 		w.WriteHeader(http.StatusNotFound)
-		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("No connected people found for person with uuid %s", uuid)})
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("No person found with uuid %s", query.UUID)})
+		w.Write([]byte(msg))
+		return
+	}
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve connected people")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error retrieving result for %s, err=%v", query.UUID, err)})
 		w.Write([]byte(msg))
 		return
 	}
 
+	if len(connectedPeople) > page.Limit {
+		connectedPeople = connectedPeople[:page.Limit]
+		last := connectedPeople[len(connectedPeople)-1]
+		hh.setNextLink(w, query, page, PageKey{Score: last.Score, Count: last.Count, UUID: uuidOf(last.Person.ID)})
+	}
+
+	// Results may be shared with other requests through the cache, so fields are dropped from copies of them. Whether
+	// they are scored comes with the query of the cursor, as the links to next pages only carry the cursor
+	selected := make([]ConnectedPerson, len(connectedPeople))
+	unscored := make([]unscoredConnectedPerson, len(connectedPeople))
+	for i, connected := range connectedPeople {
+		connected.Content = selectContentFields(connected.Content, query.Include)
+		selected[i] = connected
+		unscored[i] = unscoredConnectedPerson{Person: connected.Person, Count: connected.Count, Content: connected.Content}
+	}
+
+	setWindowHeaders(w, query)
 	w.Header().Set("Cache-Control", hh.cacheControlHeader)
 	w.WriteHeader(http.StatusOK)
 
-	json.NewEncoder(w).Encode(connectedPeople)
+	if !query.Scored {
+		json.NewEncoder(w).Encode(unscored)
+		return
+	}
+	json.NewEncoder(w).Encode(selected)
+}
+
+func (hh *Handler) GetConnectedConcepts(w http.ResponseWriter, request *http.Request) {
+	hh.getConnectedConcepts(w, request, defaultContentSort)
 }
 
-func getDateTimePeriod(fromDateParam string, toDateParam string) (fromDate time.Time, toDate time.Time, err error) {
-	fromDate, err = getDate(fromDateParam, getDefaultFromDate)
+// GetConnectedConceptsV2 attaches the newest content to each connection by default
+func (hh *Handler) GetConnectedConceptsV2(w http.ResponseWriter, request *http.Request) {
+	hh.getConnectedConcepts(w, request, defaultContentSortV2)
+}
+
+func (hh *Handler) getConnectedConcepts(w http.ResponseWriter, request *http.Request, defaultSort string) {
+	m, _ := url.ParseQuery(request.URL.RawQuery)
+
+	conceptTypeParam := m.Get("type")
+	minimumConnectionsParam := m.Get("minimumConnections")
+	resultLimitParam := m.Get("limit")
+	fromDateParam := m.Get("fromDate")
+	toDateParam := m.Get("toDate")
+	contentLimitParam := m.Get("contentLimit")
+	contentSortParam := m.Get("contentSort")
+	predicatesParam := m.Get("predicates")
+	brandParam := m.Get("brand")
+	contentTypeParam := m.Get("contentType")
+	weightingParam := m.Get("weighting")
+	minConfidenceParam := m.Get("minConfidence")
+	includeParam := m.Get("include")
+	cursorParam := m.Get("cursor")
+	uuid := m.Get("uuid")
+
+	logger := logger.WithField("uuid", uuid)
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	conceptType, err := getConceptType(conceptTypeParam, "")
 	if err != nil {
+		logger.WithError(err).Error("could not get concept type")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting type query param, err=%v", err)})
+		w.Write([]byte(msg))
 		return
 	}
 
-	toDate, err = getDate(toDateParam, getDefaultToDate)
+	fromDate, toDate, err := getDateTimePeriod(fromDateParam, toDateParam)
 	if err != nil {
+		logger.WithError(err).Error("could not get period")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting toDate or fromDate query params: fromDate=%s, toDate=%s", fromDateParam, toDateParam)})
+		w.Write([]byte(msg))
 		return
 	}
 
-	//toDate cannot be earlier than fromDate, defaulting fromDate to a week from toDate
-	if toDate.Before(fromDate) {
-		fromDate = toDate.AddDate(0, 0, -7)
+	minimumConnections, err := getLimit(minimumConnectionsParam, defaultMinConnections)
+	if err != nil {
+		logger.WithError(err).Error("could not get minimum connections limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting minimumConnections query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
 	}
 
-	// Restrict query for 1 year period based on fromDate value
-	fromDatePlusAYear := fromDate.AddDate(1, 0, 0)
-	if fromDatePlusAYear.Before(toDate) {
-		toDate = fromDatePlusAYear
+	resultLimit, err := getResultLimit(resultLimitParam, defaultConnectedPeopleResultLimit)
+	if err != nil {
+		logger.WithError(err).Error("could not get result limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting limit query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
 	}
 
-	log.Debugf("The given period is from %v to %v\n", fromDate.String(), toDate.String())
-	return
-}
+	contentLimit, err := getLimit(contentLimitParam, defaultContentLimit)
+	if err != nil {
+		logger.WithError(err).Error("could not get content limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting contentLimit query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
 
-func getDate(dateParam string, getDefaultTime defaultTimeGetter) (time.Time, error) {
-	if dateParam == "" {
-		return getDefaultTime(), nil
+	contentSort, err := getContentSort(contentSortParam, defaultSort)
+	if err != nil {
+		logger.WithError(err).Error("could not get content sort")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting contentSort query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
 	}
-	return convertDateStringToDateTime(dateParam)
-}
 
-func getLimit(limitParam string, defaultLimit int) (int, error) {
-	if limitParam == "" {
-		return defaultLimit, nil
+	filter, err := getMentionFilter(weightingParam, minConfidenceParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get mention filter")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting weighting or minConfidence query params: weighting=%s, minConfidence=%s", weightingParam, minConfidenceParam)})
+		w.Write([]byte(msg))
+		return
 	}
-	return strconv.Atoi(limitParam)
+
+	filter.Predicates, err = getPredicates(predicatesParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get predicates")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting predicates query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+	filter.Brands = splitListParam(brandParam)
+	filter.ContentTypes = splitListParam(contentTypeParam)
+
+	includes, err := getContentIncludes(includeParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get content includes")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting include query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	query := cursorQuery{
+		Path:               request.URL.Path,
+		UUID:               uuid,
+		ConceptType:        conceptType,
+		FromDateEpoch:      fromDate.Unix(),
+		ToDateEpoch:        toDate.Unix(),
+		MinimumConnections: minimumConnections,
+		ContentLimit:       contentLimit,
+		ContentSort:        contentSort,
+		Filter:             filter,
+		Include:            includes,
+	}
+	page, err := hh.getPage(cursorParam, resultLimit, &query)
+	if err != nil {
+		logger.WithError(err).Error("could not get page")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting cursor query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, request, "connectedConcepts")
+	defer cancel()
+	connectedConcepts, err := hh.driver.ConnectedConcepts(ctx, query.UUID, query.ConceptType, query.FromDateEpoch, query.ToDateEpoch, page, query.MinimumConnections, query.ContentLimit, query.ContentSort, query.Filter)
+	if err == ErrConceptNotFound {
+		if hh.redirectToCanonical(ctx, w, request, query.UUID) {
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("No concept found with uuid %s", query.UUID)})
+		w.Write([]byte(msg))
+		return
+	}
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve connected concepts")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error retrieving result for %s, err=%v", query.UUID, err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	if len(connectedConcepts) > page.Limit {
+		connectedConcepts = connectedConcepts[:page.Limit]
+		last := connectedConcepts[len(connectedConcepts)-1]
+		hh.setNextLink(w, query, page, PageKey{Score: last.Score, Count: last.Count, UUID: uuidOf(last.Concept.ID)})
+	}
+
+	// Results may be shared with other requests through the cache, so fields are dropped from copies of them
+	selected := make([]ConnectedConcept, len(connectedConcepts))
+	for i, connected := range connectedConcepts {
+		connected.Content = selectContentFields(connected.Content, query.Include)
+		selected[i] = connected
+	}
+
+	setWindowHeaders(w, query)
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(selected)
+}
+
+func (hh *Handler) GetConnection(w http.ResponseWriter, request *http.Request) {
+	m, _ := url.ParseQuery(request.URL.RawQuery)
+
+	uuid1 := m.Get("uuid1")
+	uuid2 := m.Get("uuid2")
+	fromDateParam := m.Get("fromDate")
+	toDateParam := m.Get("toDate")
+	resultLimitParam := m.Get("limit")
+	cursorParam := m.Get("cursor")
+
+	logger := logger.WithField("uuid1", uuid1).WithField("uuid2", uuid2)
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	fromDate, toDate, err := getDateTimePeriod(fromDateParam, toDateParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get period")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting toDate or fromDate query params: fromDate=%s, toDate=%s", fromDateParam, toDateParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	resultLimit, err := getResultLimit(resultLimitParam, defaultConnectionResultLimit)
+	if err != nil {
+		logger.WithError(err).Error("could not get result limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting limit query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	query := cursorQuery{
+		Path:          request.URL.Path,
+		UUID:          uuid1,
+		OtherUUID:     uuid2,
+		FromDateEpoch: fromDate.Unix(),
+		ToDateEpoch:   toDate.Unix(),
+	}
+	page, err := hh.getPage(cursorParam, resultLimit, &query)
+	if err != nil {
+		logger.WithError(err).Error("could not get page")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting cursor query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	// Checked once the cursor, if any, has given them
+	if query.UUID == "" || query.OtherUUID == "" || query.UUID == query.OtherUUID {
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Two different person uuids are required: uuid1=%s, uuid2=%s", query.UUID, query.OtherUUID)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, request, "connection")
+	defer cancel()
+	connection, err := hh.driver.Connection(ctx, query.UUID, query.OtherUUID, query.FromDateEpoch, query.ToDateEpoch, page)
+	if err == ErrConceptNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("No people found with uuids %s and %s", query.UUID, query.OtherUUID)})
+		w.Write([]byte(msg))
+		return
+	}
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve connection")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error retrieving connection between %s and %s, err=%v", query.UUID, query.OtherUUID, err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	if len(connection.Content) > page.Limit {
+		connection.Content = connection.Content[:page.Limit]
+		last := connection.Content[len(connection.Content)-1]
+		published, _ := time.Parse(time.RFC3339, last.PublishedDate)
+		hh.setNextLink(w, query, page, PageKey{Score: float64(published.Unix()), UUID: last.ID})
+	}
+
+	setWindowHeaders(w, query)
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(connection)
+}
+
+func (hh *Handler) GetShortestPath(w http.ResponseWriter, request *http.Request) {
+	m, _ := url.ParseQuery(request.URL.RawQuery)
+
+	fromUUID := m.Get("from")
+	toUUID := m.Get("to")
+	fromDateParam := m.Get("fromDate")
+	toDateParam := m.Get("toDate")
+	maxHopsParam := m.Get("maxHops")
+	contentLimitParam := m.Get("contentLimit")
+
+	logger := logger.WithField("from", fromUUID).WithField("to", toUUID)
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	if fromUUID == "" || toUUID == "" || fromUUID == toUUID {
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Two different person uuids are required: from=%s, to=%s", fromUUID, toUUID)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	fromDate, toDate, err := getDateTimePeriod(fromDateParam, toDateParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get period")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting toDate or fromDate query params: fromDate=%s, toDate=%s", fromDateParam, toDateParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	maxHops, err := getLimit(maxHopsParam, defaultMaxHops)
+	if err == nil && (maxHops < 1 || maxHops > defaultMaxHops) {
+		err = fmt.Errorf("maxHops must be between 1 and %d", defaultMaxHops)
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not get max hops")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting maxHops query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	contentLimit, err := getLimit(contentLimitParam, defaultContentLimit)
+	if err != nil {
+		logger.WithError(err).Error("could not get content limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting contentLimit query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, request, "path")
+	defer cancel()
+	path, found, err := hh.driver.ShortestPath(ctx, fromUUID, toUUID, fromDate.Unix(), toDate.Unix(), maxHops, contentLimit)
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve shortest path")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error retrieving path between %s and %s, err=%v", fromUUID, toUUID, err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("No path found between people with uuids %s and %s", fromUUID, toUUID)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(path)
+}
+
+func (hh *Handler) GetNetwork(w http.ResponseWriter, request *http.Request) {
+	m, _ := url.ParseQuery(request.URL.RawQuery)
+
+	depthParam := m.Get("depth")
+	minimumConnectionsParam := m.Get("minimumConnections")
+	resultLimitParam := m.Get("limit")
+	fromDateParam := m.Get("fromDate")
+	toDateParam := m.Get("toDate")
+	formatParam := m.Get("format")
+	uuid := m.Get("uuid")
+
+	logger := logger.WithField("uuid", uuid)
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	fromDate, toDate, err := getDateTimePeriod(fromDateParam, toDateParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get period")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting toDate or fromDate query params: fromDate=%s, toDate=%s", fromDateParam, toDateParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	format, err := getNetworkFormat(formatParam, request.Header.Get("Accept"))
+	if err != nil {
+		logger.WithError(err).Error("could not get format")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting format query param, %v: format=%s", err, formatParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	depth, err := getLimit(depthParam, defaultNetworkDepth)
+	if err == nil && (depth < 1 || depth > maxNetworkDepth) {
+		err = fmt.Errorf("depth must be between 1 and %d", maxNetworkDepth)
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not get depth")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting depth query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	minimumConnections, err := getLimit(minimumConnectionsParam, defaultMinConnections)
+	if err == nil && minimumConnections < 0 {
+		err = errors.New("minimumConnections must not be negative")
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not get minimum connections limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting minimumConnections query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	// Every ring is queried for up to the limit, and the edges between all of them
+	resultLimit, err := getLimit(resultLimitParam, defaultConnectedPeopleResultLimit)
+	if err == nil && (resultLimit < 1 || resultLimit > maxNetworkResultLimit) {
+		err = fmt.Errorf("limit must be between 1 and %d", maxNetworkResultLimit)
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not get result limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting limit query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, request, "network")
+	defer cancel()
+	network, found, err := hh.driver.Network(ctx, uuid, fromDate.Unix(), toDate.Unix(), depth, resultLimit, minimumConnections)
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve network")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error retrieving network for %s, err=%v", uuid, err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	if !found {
+		if hh.redirectToCanonical(ctx, w, request, uuid) {
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("No network found for person with uuid %s", uuid)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Content-Type", networkFormats[format].mediaType)
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(http.StatusOK)
+
+	if err := networkFormats[format].encode(w, network); err != nil {
+		logger.WithError(err).Errorf("could not encode network as %s", format)
+	}
+}
+
+func (hh *Handler) GetCommunities(w http.ResponseWriter, request *http.Request) {
+	m, _ := url.ParseQuery(request.URL.RawQuery)
+
+	maxNodesParam := m.Get("maxNodes")
+	minimumConnectionsParam := m.Get("minimumConnections")
+	fromDateParam := m.Get("fromDate")
+	toDateParam := m.Get("toDate")
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	fromDate, toDate, err := getDateTimePeriod(fromDateParam, toDateParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get period")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting toDate or fromDate query params: fromDate=%s, toDate=%s", fromDateParam, toDateParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	maxNodes, err := getLimit(maxNodesParam, defaultCommunitiesMaxNodes)
+	if err == nil && (maxNodes < 1 || maxNodes > maxCommunitiesMaxNodes) {
+		err = fmt.Errorf("maxNodes must be between 1 and %d", maxCommunitiesMaxNodes)
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not get max nodes")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting maxNodes query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	minimumConnections, err := getLimit(minimumConnectionsParam, defaultMinConnections)
+	if err != nil {
+		logger.WithError(err).Error("could not get minimum connections limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting minimumConnections query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, request, "communities")
+	defer cancel()
+	graph, found, err := hh.driver.CoMentionGraph(ctx, fromDate.Unix(), toDate.Unix(), maxNodes, minimumConnections)
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve co-mention graph")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{"Error retrieving result from DB"})
+		w.Write([]byte(msg))
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		msg, _ := json.Marshal(ErrorMessage{"No result"})
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(detectCommunities(graph))
+}
+
+func (hh *Handler) GetCentralPeople(w http.ResponseWriter, request *http.Request) {
+	m, _ := url.ParseQuery(request.URL.RawQuery)
+
+	metricParam := m.Get("metric")
+	limitParam := m.Get("limit")
+	maxNodesParam := m.Get("maxNodes")
+	minimumConnectionsParam := m.Get("minimumConnections")
+	fromDateParam := m.Get("fromDate")
+	toDateParam := m.Get("toDate")
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	metric := metricParam
+	if metric == "" {
+		metric = defaultCentralityMetric
+	}
+	if !isKnownCentralityMetric(metric) {
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting metric query param, must be one of pagerank, betweenness, degree: metric=%s", metricParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	fromDate, toDate, err := getDateTimePeriod(fromDateParam, toDateParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get period")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting toDate or fromDate query params: fromDate=%s, toDate=%s", fromDateParam, toDateParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	limit, err := getLimit(limitParam, defaultCentralPeopleResultLimit)
+	if err == nil && limit < 1 {
+		err = errors.New("limit must be at least 1")
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not get limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting limit query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	maxNodes, err := getLimit(maxNodesParam, defaultCentralityMaxNodes)
+	if err == nil && (maxNodes < 1 || maxNodes > maxCentralityMaxNodes) {
+		err = fmt.Errorf("maxNodes must be between 1 and %d", maxCentralityMaxNodes)
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not get max nodes")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting maxNodes query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	minimumConnections, err := getLimit(minimumConnectionsParam, defaultMinConnections)
+	if err != nil {
+		logger.WithError(err).Error("could not get minimum connections limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting minimumConnections query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, request, "centralPeople")
+	defer cancel()
+	ranking, found, err := hh.driver.CentralPeople(ctx, metric, fromDate.Unix(), toDate.Unix(), maxNodes, minimumConnections)
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve central people")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{"Error retrieving result from DB"})
+		w.Write([]byte(msg))
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		msg, _ := json.Marshal(ErrorMessage{"No result"})
+		w.Write([]byte(msg))
+		return
+	}
+
+	if len(ranking) > limit {
+		ranking = ranking[:limit]
+	}
+
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(ranking)
+}
+
+// GetPeopleSearch finds people by the start of their names, for clients to get their UUIDs from.
+// Finding no one is not an error, as the query is often still being typed
+func (hh *Handler) GetPeopleSearch(w http.ResponseWriter, request *http.Request) {
+	m, _ := url.ParseQuery(request.URL.RawQuery)
+
+	queryParam := strings.TrimSpace(m.Get("q"))
+	limitParam := m.Get("limit")
+	fromDateParam := m.Get("fromDate")
+	toDateParam := m.Get("toDate")
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	if queryParam == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{"Error converting q query param, err=q is required"})
+		w.Write([]byte(msg))
+		return
+	}
+	// Shorter queries match too many people to be worth answering while they are still being typed
+	if utf8.RuneCountInString(queryParam) < minPeopleSearchQueryLength {
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting q query param, err=q must be at least %d characters", minPeopleSearchQueryLength)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	limit, err := getLimit(limitParam, defaultPeopleSearchResultLimit)
+	if err == nil && (limit < 1 || limit > maxPeopleSearchResultLimit) {
+		err = fmt.Errorf("limit must be between 1 and %d", maxPeopleSearchResultLimit)
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not get limit")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting limit query param, err=%v", err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	fromDate, toDate, err := getDateTimePeriod(fromDateParam, toDateParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get period")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting toDate or fromDate query params: fromDate=%s, toDate=%s", fromDateParam, toDateParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, request, "peopleSearch")
+	defer cancel()
+	people, err := hh.driver.SearchPeople(ctx, queryParam, fromDate.Unix(), toDate.Unix(), limit)
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).WithField("q", queryParam).Error("could not search people")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{"Error retrieving result from DB"})
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(people)
+}
+
+func (hh *Handler) GetMentions(w http.ResponseWriter, request *http.Request) {
+	m, _ := url.ParseQuery(request.URL.RawQuery)
+
+	intervalParam := m.Get("interval")
+	fromDateParam := m.Get("fromDate")
+	toDateParam := m.Get("toDate")
+	uuid := mux.Vars(request)["uuid"]
+
+	logger := logger.WithField("uuid", uuid)
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	fromDate, toDate, err := getDateTimePeriod(fromDateParam, toDateParam)
+	if err != nil {
+		logger.WithError(err).Error("could not get period")
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting toDate or fromDate query params: fromDate=%s, toDate=%s", fromDateParam, toDateParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	interval := defaultMentionsInterval
+	if intervalParam != "" {
+		interval = intervalParam
+	}
+	if !isKnownMentionsInterval(interval) {
+		w.WriteHeader(http.StatusBadRequest)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error converting interval query param, must be one of %s: interval=%s", strings.Join(mentionsIntervals, ", "), intervalParam)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	ctx, cancel := hh.queryContext(w, request, "mentions")
+	defer cancel()
+	buckets, found, err := hh.driver.Mentions(ctx, uuid, fromDate.Unix(), toDate.Unix(), interval)
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("could not retrieve mentions")
+		w.WriteHeader(http.StatusInternalServerError)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("Error retrieving mentions for %s, err=%v", uuid, err)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("No person found with uuid %s", uuid)})
+		w.Write([]byte(msg))
+		return
+	}
+
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(buckets)
+}
+
+// The period actually searched, once defaulted and clamped, so that an empty list can be told apart from a wrong period
+func setWindowHeaders(w http.ResponseWriter, query cursorQuery) {
+	w.Header().Set("X-From-Date", time.Unix(query.FromDateEpoch, 0).UTC().Format(time.RFC3339))
+	w.Header().Set("X-To-Date", time.Unix(query.ToDateEpoch, 0).UTC().Format(time.RFC3339))
+}
+
+// redirectToCanonical redirects to the same request for the canonical UUID of a concept, when it was asked for by one of
+// its other identifiers. Identifiers are only resolved once nothing is found, so canonical UUIDs cost no extra query
+func (hh *Handler) redirectToCanonical(ctx context.Context, w http.ResponseWriter, request *http.Request, identifier string) bool {
+	canonicalUUID, found, err := hh.driver.CanonicalUUID(ctx, identifier)
+	if err != nil {
+		logger.WithError(err).WithField("uuid", identifier).Error("could not resolve identifier")
+		return false
+	}
+	if !found || canonicalUUID == identifier {
+		return false
+	}
+
+	params := request.URL.Query()
+	params.Set("uuid", canonicalUUID)
+	w.Header().Set("Location", request.URL.Path+"?"+params.Encode())
+	w.Header().Set("Cache-Control", hh.cacheControlHeader)
+	w.WriteHeader(http.StatusMovedPermanently)
+	msg, _ := json.Marshal(ErrorMessage{fmt.Sprintf("%s identifies the concept with uuid %s", identifier, canonicalUUID)})
+	w.Write([]byte(msg))
+	return true
+}
+
+func getMentionFilter(weightingParam string, minConfidenceParam string) (MentionFilter, error) {
+	filter := MentionFilter{Weighting: defaultWeighting}
+	if weightingParam != "" {
+		filter.Weighting = weightingParam
+	}
+	if _, ok := mentionWeightings[filter.Weighting]; !ok {
+		return filter, fmt.Errorf("unknown weighting %s", filter.Weighting)
+	}
+
+	if minConfidenceParam != "" {
+		minConfidence, err := strconv.ParseFloat(minConfidenceParam, 64)
+		if err != nil {
+			return filter, err
+		}
+		if minConfidence < 0 || minConfidence > 1 {
+			return filter, errors.New("minConfidence must be between 0 and 1")
+		}
+		filter.MinConfidence = minConfidence
+	}
+	return filter, nil
+}
+
+func getPredicates(predicatesParam string) ([]string, error) {
+	if predicatesParam == "" {
+		return []string{defaultPredicate}, nil
+	}
+
+	predicates := splitListParam(predicatesParam)
+	return predicates, validatePredicates(predicates)
+}
+
+func getContentSort(contentSortParam string, defaultSort string) (string, error) {
+	if contentSortParam == "" {
+		return defaultSort, nil
+	}
+	if !isKnownContentSort(contentSortParam) {
+		return "", fmt.Errorf("unknown content sort %s, must be one of %s", contentSortParam, strings.Join(contentSortNames, ", "))
+	}
+	return contentSortParam, nil
+}
+
+// Content fields are left out of connections unless included, to keep the default payload small
+var contentIncludes = []string{"publishedDate", "type", "brands", "predicates"}
+
+func getContentIncludes(includeParam string) ([]string, error) {
+	includes := splitListParam(includeParam)
+	for _, include := range includes {
+		if !isContentInclude(include) {
+			return nil, fmt.Errorf("unknown field %s, must be one of %s", include, strings.Join(contentIncludes, ", "))
+		}
+	}
+	return includes, nil
+}
+
+func isContentInclude(field string) bool {
+	for _, include := range contentIncludes {
+		if field == include {
+			return true
+		}
+	}
+	return false
+}
+
+func selectContentFields(contentList []Content, includes []string) []Content {
+	included := map[string]bool{}
+	for _, include := range includes {
+		included[include] = true
+	}
+
+	if contentList == nil {
+		return nil
+	}
+	selected := make([]Content, len(contentList))
+	for i, content := range contentList {
+		if !included["publishedDate"] {
+			content.PublishedDate = ""
+		}
+		if !included["type"] {
+			content.Type = ""
+		}
+		if !included["brands"] {
+			content.Brands = nil
+		}
+		if !included["predicates"] {
+			content.Predicates = nil
+		}
+		selected[i] = content
+	}
+	return selected
+}
+
+func splitListParam(listParam string) []string {
+	var list []string
+	for _, value := range strings.Split(listParam, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+func getDateTimePeriod(fromDateParam string, toDateParam string) (fromDate time.Time, toDate time.Time, err error) {
+	fromDate, err = getDate(fromDateParam, getDefaultFromDate)
+	if err != nil {
+		return
+	}
+
+	toDate, err = getDate(toDateParam, getDefaultToDate)
+	if err != nil {
+		return
+	}
+
+	fromDate, toDate = clampDateTimePeriod(fromDate, toDate)
+
+	log.Debugf("The given period is from %v to %v\n", fromDate.String(), toDate.String())
+	return
+}
+
+// The previous period has the same length as the given one and ends where it starts
+func getPreviousDateTimePeriod(fromDate time.Time, toDate time.Time) (time.Time, time.Time) {
+	return clampDateTimePeriod(fromDate.Add(-toDate.Sub(fromDate)), fromDate)
+}
+
+func clampDateTimePeriod(fromDate time.Time, toDate time.Time) (time.Time, time.Time) {
+	//toDate cannot be earlier than fromDate, defaulting fromDate to a week from toDate
+	if toDate.Before(fromDate) {
+		fromDate = toDate.AddDate(0, 0, -7)
+	}
+
+	// Restrict query for 1 year period based on fromDate value
+	fromDatePlusAYear := fromDate.AddDate(1, 0, 0)
+	if fromDatePlusAYear.Before(toDate) {
+		toDate = fromDatePlusAYear
+	}
+
+	return fromDate, toDate
+}
+
+func getDate(dateParam string, getDefaultTime defaultTimeGetter) (time.Time, error) {
+	if dateParam == "" {
+		return getDefaultTime(), nil
+	}
+	return convertDateStringToDateTime(dateParam)
+}
+
+func getLimit(limitParam string, defaultLimit int) (int, error) {
+	if limitParam == "" {
+		return defaultLimit, nil
+	}
+	return strconv.Atoi(limitParam)
+}
+
+// getResultLimit is the size of a page of results, which must hold at least one so that the next page can follow it
+func getResultLimit(limitParam string, defaultLimit int) (int, error) {
+	limit, err := getLimit(limitParam, defaultLimit)
+	if err == nil && (limit < 1 || limit > maxResultLimit) {
+		err = fmt.Errorf("limit must be between 1 and %d", maxResultLimit)
+	}
+	return limit, err
+}
+
+func getConceptType(conceptTypeParam string, defaultType string) (string, error) {
+	if conceptTypeParam == "" {
+		if defaultType == "" {
+			return "", errors.New("type is required")
+		}
+		return defaultType, nil
+	}
+	for _, conceptType := range conceptTypes {
+		if strings.EqualFold(conceptType, conceptTypeParam) {
+			return conceptType, nil
+		}
+	}
+	return "", fmt.Errorf("unknown concept type %s, must be one of %s", conceptTypeParam, strings.Join(conceptTypes, ", "))
 }
 
 func convertDateStringToDateTime(dateString string) (time.Time, error) {
@@ -2,20 +2,29 @@ package sixdegrees
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	fthealth "github.com/Financial-Times/go-fthealth/v1_1"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-const knownUUID = "12345"
+const (
+	knownUUID      = "12345"
+	otherKnownUUID = "67890"
+)
+
+var testCursorSecret = []byte("test cursor secret")
 
 type handlerTestCase struct {
 	name                       string
@@ -29,6 +38,17 @@ type handlerTestCase struct {
 	expectedToDateEpoch        int64
 	expectedMinimumConnections int
 	expectedContentLimit       int
+	expectedMaxHops            int
+	expectedDepth              int
+	expectedConceptType        string
+	expectedPreviousFromEpoch  int64
+	expectedPreviousToEpoch    int64
+	expectedRankBy             string
+	expectedInterval           string
+	expectedFilter             MentionFilter
+	expectedMaxNodes           int
+	expectedContentSort        string
+	expectedQuery              string
 }
 
 func TestGetConnectedPeople(t *testing.T) {
@@ -151,6 +171,187 @@ func TestGetConnectedPeople(t *testing.T) {
 			expectedMinimumConnections: 0,
 			expectedContentLimit:       0,
 		},
+		{
+			name:                       "SuccessWithWeightingAndMinConfidence",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&weighting=relevance&minConfidence=0.8", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       "[]",
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedFilter:             MentionFilter{Predicates: []string{"mentions"}, Weighting: "relevance", MinConfidence: 0.8},
+		},
+		{
+			name:                       "SuccessWithPredicates",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&predicates=about,hasAuthor", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       "[]",
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedFilter:             MentionFilter{Predicates: []string{"about", "hasAuthor"}, Weighting: defaultWeighting},
+		},
+		{
+			name:                       "SuccessWithBrandsAndContentTypes",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&brand=dbb0bdae-1f0c-11e4-b0cb-b2227cce2b54,%s&contentType=Video", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       "[]",
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedFilter:             MentionFilter{Predicates: []string{"mentions"}, Weighting: defaultWeighting, Brands: []string{"dbb0bdae-1f0c-11e4-b0cb-b2227cce2b54", otherKnownUUID}, ContentTypes: []string{"Video"}},
+		},
+		{
+			name:                       "SuccessWithoutContentFields",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"person": {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person"}, "count": 5, "content": [{"id": "c1", "apiUrl": "http://api.ft.com/content/c1", "title": "Some Title"}]}]`,
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+		},
+		{
+			name:                       "SuccessWithIncludedContentFields",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&include=publishedDate,brands", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"person": {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person"}, "count": 5, "content": [{"id": "c1", "apiUrl": "http://api.ft.com/content/c1", "title": "Some Title", "publishedDate": "2016-12-13T19:18:01Z", "brands": [{"id": "http://api.ft.com/things/b1", "prefLabel": "Some Brand"}]}]}]`,
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+		},
+		{
+			name:                       "SuccessWithAllContentFields",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&include=publishedDate,type,brands,predicates", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"person": {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person"}, "count": 5, "content": [{"id": "c1", "apiUrl": "http://api.ft.com/content/c1", "title": "Some Title", "publishedDate": "2016-12-13T19:18:01Z", "type": "Article", "brands": [{"id": "http://api.ft.com/things/b1", "prefLabel": "Some Brand"}], "predicates": ["about", "mentions"]}]}]`,
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+		},
+		{
+			name:                       "SuccessWithContentSort",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&contentSort=relevance", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       "[]",
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedContentSort:        "relevance",
+		},
+		{
+			name:                       "SuccessWithScoresWhenWeighted",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&weighting=count", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"person": {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person"}, "count": 5, "score": 5, "content": [{"id": "c1", "apiUrl": "http://api.ft.com/content/c1", "title": "Some Title"}]}]`,
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+		},
+		{
+			name:                       "SuccessV2WithScores",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/v2/connectedPeople?uuid=%s", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"person": {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person"}, "count": 5, "score": 5, "content": [{"id": "c1", "apiUrl": "http://api.ft.com/content/c1", "title": "Some Title"}]}]`,
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedContentSort:        "newest",
+		},
+		{
+			name:                       "SuccessV2SortsNewestContentFirst",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/v2/connectedPeople?uuid=%s", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       "[]",
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedContentSort:        "newest",
+		},
+		{
+			name:                       "SuccessV2WithContentSort",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/v2/connectedPeople?uuid=%s&contentSort=oldest", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       "[]",
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedContentSort:        "oldest",
+		},
+		{
+			name:       "FailureWithUnknownContentSort",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&contentSort=random", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting contentSort query param, err=unknown content sort random, must be one of newest, oldest, relevance"),
+		},
+		{
+			name:       "FailureWithUnknownInclude",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&include=publishedDate,body", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting include query param, err=unknown field body, must be one of publishedDate, type, brands, predicates"),
+		},
+		{
+			name:       "FailureWithUnknownPredicate",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&predicates=mentions,FAIL", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting predicates query param, err=unknown predicate FAIL, must be one of about, hasAuthor, hasDisplayTag, isClassifiedBy, isPrimarilyClassifiedBy, majorMentions, mentions"),
+		},
+		{
+			name:       "FailureWithUnknownWeighting",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&weighting=FAIL", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting weighting or minConfidence query params: weighting=FAIL, minConfidence="),
+		},
+		{
+			name:       "FailureWithInvalidMinConfidence",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&minConfidence=FAIL", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting weighting or minConfidence query params: weighting=, minConfidence=FAIL"),
+		},
+		{
+			name:       "FailureWithMinConfidenceOutOfRange",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&minConfidence=1.5", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting weighting or minConfidence query params: weighting=, minConfidence=1.5"),
+		},
 		{
 			name:                       "FailureWithInvalidMinConnections",
 			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&minimumConnections=FAIL", knownUUID), "application/json", nil),
@@ -170,7 +371,7 @@ func TestGetConnectedPeople(t *testing.T) {
 			driver:                     &dummyDriver{contentUUID: knownUUID},
 			statusCode:                 http.StatusNotFound,
 			contentType:                "",
-			body:                       message("No connected people found for person with uuid 99999"),
+			body:                       message("No person found with uuid 99999"),
 			expectedResultLimit:        defaultConnectedPeopleResultLimit,
 			expectedFromDateEpoch:      time.Now().AddDate(0, 0, -7).Unix(),
 			expectedToDateEpoch:        time.Now().Unix(),
@@ -190,12 +391,25 @@ func TestGetConnectedPeople(t *testing.T) {
 			expectedMinimumConnections: defaultMinConnections,
 			expectedContentLimit:       defaultContentLimit,
 		},
+		{
+			name:                       "TimeoutError",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID, shouldTimeOut: true},
+			statusCode:                 http.StatusGatewayTimeout,
+			contentType:                "",
+			body:                       message("Timed out retrieving result from DB"),
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      time.Now().AddDate(0, 0, -7).Unix(),
+			expectedToDateEpoch:        time.Now().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+		},
 	}
 
 	for _, test := range tests {
 		rec := httptest.NewRecorder()
 		router := mux.NewRouter()
-		handler := Handler{test.driver, "max-age=360, public"}
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
 		handler.RegisterHandlers(router)
 		router.ServeHTTP(rec, test.req)
 		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
@@ -205,9 +419,160 @@ func TestGetConnectedPeople(t *testing.T) {
 		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
 		assert.Equal(test.expectedMinimumConnections, test.driver.argMinimumConnections, fmt.Sprintf("%s: Wrong minimum connections", test.name))
 		assert.Equal(test.expectedContentLimit, test.driver.argContentLimit, fmt.Sprintf("%s: Wrong content limit", test.name))
+		assert.Equal(test.expectedContentSort, test.driver.argContentSort, fmt.Sprintf("%s: Wrong content sort", test.name))
+		if test.expectedFilter.Weighting != "" {
+			assert.Equal(test.expectedFilter, test.driver.argFilter, fmt.Sprintf("%s: Wrong mention filter", test.name))
+		}
+	}
+}
+
+func TestGetConnectedPeopleWithoutConnections(t *testing.T) {
+	assert := assert.New(t)
+	router := mux.NewRouter()
+	NewHandler(&dummyDriver{contentUUID: knownUUID}, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+
+	for _, path := range []string{"/sixdegrees/connectedPeople", "/sixdegrees/connectedConcepts"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, newRequest("GET", fmt.Sprintf("%s?uuid=%s&type=Person&fromDate=2017-01-10&toDate=2017-01-20", path, knownUUID), "application/json", nil))
+		assert.Equal(http.StatusOK, rec.Code, fmt.Sprintf("%s: A known concept without connections should be found", path))
+		assert.JSONEq("[]", rec.Body.String(), fmt.Sprintf("%s: Wrong body", path))
+		assert.Equal("2017-01-10T00:00:00Z", rec.Header().Get("X-From-Date"), fmt.Sprintf("%s: Wrong from date", path))
+		assert.Equal("2017-01-20T00:00:00Z", rec.Header().Get("X-To-Date"), fmt.Sprintf("%s: Wrong to date", path))
+	}
+}
+
+func TestGetConnectedPeopleBatch(t *testing.T) {
+	assert := assert.New(t)
+	connected := `[{"person": {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person"}, "count": 5, "score": 5, "content": [{"id": "c1", "apiUrl": "http://api.ft.com/content/c1", "title": "Some Title", "publishedDate": "2016-12-13T19:18:01Z", "type": "Article", "brands": [{"id": "http://api.ft.com/things/b1", "prefLabel": "Some Brand"}], "predicates": ["about", "mentions"]}]}]`
+	tests := []struct {
+		name       string
+		body       string
+		driver     *dummyDriver
+		statusCode int
+		expected   string
+	}{
+		{
+			name:       "Success",
+			body:       fmt.Sprintf(`{"items": [{"uuid": "%s"}, {"uuid": "99999"}]}`, knownUUID),
+			driver:     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode: http.StatusOK,
+			expected:   fmt.Sprintf(`{"%s": {"status": 200, "connectedPeople": %s}, "99999": {"status": 404, "message": "No person found with uuid 99999"}}`, knownUUID, connected),
+		},
+		{
+			name:       "SuccessWithFailingItems",
+			body:       fmt.Sprintf(`{"items": [{"uuid": "%s", "contentSort": "FAIL"}, {"uuid": "%s", "fromDate": "FAIL"}]}`, knownUUID, otherKnownUUID),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusOK,
+			expected:   fmt.Sprintf(`{"%s": {"status": 400, "message": "Error converting contentSort param, err=unknown content sort FAIL, must be one of newest, oldest, relevance"}, "%s": {"status": 400, "message": "Error converting toDate or fromDate params: fromDate=FAIL, toDate="}}`, knownUUID, otherKnownUUID),
+		},
+		{
+			name:       "ReadError",
+			body:       fmt.Sprintf(`{"items": [{"uuid": "%s"}]}`, knownUUID),
+			driver:     &dummyDriver{contentUUID: knownUUID, shouldFail: true},
+			statusCode: http.StatusOK,
+			expected:   fmt.Sprintf(`{"%s": {"status": 500, "message": "Error retrieving result for %s, err=TEST failing to READ"}}`, knownUUID, knownUUID),
+		},
+		{
+			name:       "TimeoutError",
+			body:       fmt.Sprintf(`{"items": [{"uuid": "%s"}]}`, knownUUID),
+			driver:     &dummyDriver{contentUUID: knownUUID, shouldTimeOut: true},
+			statusCode: http.StatusOK,
+			expected:   fmt.Sprintf(`{"%s": {"status": 504, "message": "Timed out retrieving result from DB"}}`, knownUUID),
+		},
+		{
+			name:       "FailureWithoutItems",
+			body:       `{"items": []}`,
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			expected:   message("Error converting batch body, err=items must hold between 1 and 50 people"),
+		},
+		{
+			name:       "FailureWithRepeatedUUID",
+			body:       fmt.Sprintf(`{"items": [{"uuid": "%s"}, {"uuid": "%s"}]}`, knownUUID, knownUUID),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			expected:   message(fmt.Sprintf("Error converting batch body, err=uuid %s is repeated", knownUUID)),
+		},
+		{
+			name:       "FailureWithNegativeItemLimit",
+			body:       fmt.Sprintf(`{"items": [{"uuid": "%s", "limit": -1}]}`, knownUUID),
+			driver:     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode: http.StatusBadRequest,
+			expected:   message(fmt.Sprintf("Error converting batch body, err=limit must be between 1 and 1000 for uuid %s", knownUUID)),
+		},
+		{
+			name:       "FailureWithZeroItemLimit",
+			body:       fmt.Sprintf(`{"limit": 5, "items": [{"uuid": "%s", "limit": 0}]}`, knownUUID),
+			driver:     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode: http.StatusBadRequest,
+			expected:   message(fmt.Sprintf("Error converting batch body, err=limit must be between 1 and 1000 for uuid %s", knownUUID)),
+		},
+		{
+			name:       "FailureWithNegativeSharedContentLimit",
+			body:       fmt.Sprintf(`{"contentLimit": -1, "items": [{"uuid": "%s"}]}`, knownUUID),
+			driver:     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode: http.StatusBadRequest,
+			expected:   message("Error converting batch body, err=contentLimit must not be negative"),
+		},
+		{
+			name:       "FailureWithNegativeMinimumConnections",
+			body:       fmt.Sprintf(`{"items": [{"uuid": "%s", "minimumConnections": -2}]}`, knownUUID),
+			driver:     &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople()},
+			statusCode: http.StatusBadRequest,
+			expected:   message(fmt.Sprintf("Error converting batch body, err=minimumConnections must not be negative for uuid %s", knownUUID)),
+		},
+		{
+			name:       "FailureWithMalformedBody",
+			body:       `{"items": [`,
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			expected:   message("Error converting batch body, err=unexpected EOF"),
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+		router.ServeHTTP(rec, newRequest("POST", "/sixdegrees/connectedPeople:batch", "application/json", []byte(test.body)))
+		assert.Equal(test.statusCode, rec.Code, fmt.Sprintf("%s: Wrong response code", test.name))
+		assert.JSONEq(test.expected, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
 	}
 }
 
+func TestGetConnectedPeopleBatchParams(t *testing.T) {
+	assert := assert.New(t)
+	driver := &dummyDriver{contentUUID: knownUUID}
+	router := mux.NewRouter()
+	NewHandler(driver, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+
+	body := fmt.Sprintf(`{"fromDate": "2017-05-01", "toDate": "2017-05-08", "limit": 5, "contentLimit": 2, "items": [{"uuid": "%s", "contentLimit": 4, "contentSort": "oldest"}]}`, knownUUID)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("POST", "/sixdegrees/connectedPeople:batch", "application/json", []byte(body)))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC).Unix(), driver.argFromDateEpoch, "Shared params should apply to every item")
+	assert.Equal(5, driver.argLimit, "Shared params should apply to every item")
+	assert.Equal(defaultMinConnections, driver.argMinimumConnections, "Params given nowhere should default")
+	assert.Equal(4, driver.argContentLimit, "Params of an item should override the shared ones")
+	assert.Equal("oldest", driver.argContentSort, "Params of an item should override the shared ones")
+	assert.Equal([]string{defaultPredicate}, driver.argFilter.Predicates, "Filters should default")
+}
+
+func TestGetConnectedPeopleBatchZeroParams(t *testing.T) {
+	assert := assert.New(t)
+	driver := &dummyDriver{contentUUID: knownUUID}
+	router := mux.NewRouter()
+	NewHandler(driver, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+
+	body := fmt.Sprintf(`{"minimumConnections": 2, "contentLimit": 2, "items": [{"uuid": "%s", "minimumConnections": 0, "contentLimit": 0}]}`, knownUUID)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("POST", "/sixdegrees/connectedPeople:batch", "application/json", []byte(body)))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(0, driver.argMinimumConnections, "Zero params of an item should override the shared ones")
+	assert.Equal(0, driver.argContentLimit, "Zero params of an item should override the shared ones")
+	assert.Equal(defaultConnectedPeopleResultLimit, driver.argLimit, "Params given nowhere should default")
+}
+
 func TestGetMostMentionedPeople(t *testing.T) {
 	assert := assert.New(t)
 	tests := []handlerTestCase{
@@ -282,6 +647,55 @@ func TestGetMostMentionedPeople(t *testing.T) {
 			contentType: "",
 			body:        message("Error converting toDate or fromDate query params: fromDate=, toDate=FAIL"),
 		},
+		{
+			name:                  "SuccessWithWeightingAndMinConfidence",
+			req:                   newRequest("GET", "/sixdegrees/mostMentionedPeople?weighting=confidence&minConfidence=0.5", "application/json", nil),
+			driver:                &dummyDriver{},
+			statusCode:            http.StatusOK,
+			body:                  "[]",
+			expectedResultLimit:   defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedFilter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: "confidence", MinConfidence: 0.5},
+		},
+		{
+			name:                  "SuccessWithPredicates",
+			req:                   newRequest("GET", "/sixdegrees/mostMentionedPeople?predicates=mentions,about", "application/json", nil),
+			driver:                &dummyDriver{},
+			statusCode:            http.StatusOK,
+			body:                  "[]",
+			expectedResultLimit:   defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedFilter:        MentionFilter{Predicates: []string{"mentions", "about"}, Weighting: defaultWeighting},
+		},
+		{
+			name:                  "SuccessWithBrandsAndContentTypes",
+			req:                   newRequest("GET", "/sixdegrees/mostMentionedPeople?brand=12345&contentType=Article,%20LiveBlogPost", "application/json", nil),
+			driver:                &dummyDriver{},
+			statusCode:            http.StatusOK,
+			body:                  "[]",
+			expectedResultLimit:   defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedFilter:        MentionFilter{Predicates: []string{"mentions"}, Weighting: defaultWeighting, Brands: []string{"12345"}, ContentTypes: []string{"Article", "LiveBlogPost"}},
+		},
+		{
+			name:        "FailureWithUnknownPredicate",
+			req:         newRequest("GET", "/sixdegrees/mostMentionedPeople?predicates=FAIL", "application/json", nil),
+			driver:      &dummyDriver{},
+			statusCode:  http.StatusBadRequest,
+			contentType: "",
+			body:        message("Error converting predicates query param, err=unknown predicate FAIL, must be one of about, hasAuthor, hasDisplayTag, isClassifiedBy, isPrimarilyClassifiedBy, majorMentions, mentions"),
+		},
+		{
+			name:        "FailureWithUnknownWeighting",
+			req:         newRequest("GET", "/sixdegrees/mostMentionedPeople?weighting=FAIL", "application/json", nil),
+			driver:      &dummyDriver{},
+			statusCode:  http.StatusBadRequest,
+			contentType: "",
+			body:        message("Error converting weighting or minConfidence query params: weighting=FAIL, minConfidence="),
+		},
 		{
 			name:        "FailureWithInvalidResultLimit",
 			req:         newRequest("GET", "/sixdegrees/mostMentionedPeople?limit=FAIL", "application/json", nil),
@@ -312,52 +726,1226 @@ func TestGetMostMentionedPeople(t *testing.T) {
 			expectedFromDateEpoch: time.Now().AddDate(0, 0, -7).Unix(),
 			expectedToDateEpoch:   time.Now().Unix(),
 		},
-	}
-
-	for _, test := range tests {
-		rec := httptest.NewRecorder()
-		router := mux.NewRouter()
-		handler := Handler{test.driver, "max-age=360, public"}
-		handler.RegisterHandlers(router)
-		router.ServeHTTP(rec, test.req)
-		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
-		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
-		assert.Equal(test.expectedResultLimit, test.driver.argLimit, fmt.Sprintf("%s: Wrong limit", test.name))
-		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
-		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
-	}
-}
-func TestCheckConnectivity(t *testing.T) {
-	assert := assert.New(t)
-
-	timeTemplate := "2006-01-02T15:04:05.000Z"
-
-	testSuccessTimeStr := "2016-12-13T16:03:31.2382547+02:00"
-	testSuccessTime, _ := time.Parse(timeTemplate, testSuccessTimeStr)
-
-	testSuccessResponse := fthealth.HealthResult{
-		SchemaVersion: 1,
-		SystemCode:    "public-six-degrees-api",
-		Name:          "Public Six Degrees API",
-		Description:   "Six Degrees Backend provides mostMentionedPeople and connectedPeople endpoints for Six Degrees Frontend.",
-		Ok:            true,
-		Checks: []fthealth.CheckResult{
-			{
-				Name:             "Check connectivity to Neo4j - neoUrl is a parameter in hieradata for this service",
-				Ok:               true,
-				Severity:         3,
-				BusinessImpact:   "Unable to respond to Public Six Degrees",
-				PanicGuide:       "https://dewey.ft.com/public-six-degrees-api.html",
-				TechnicalSummary: "Cannot connect to Neo4j. If this check fails, check that Neo4j instance is up and running. You can find\n\t\t\t\tthe neoUrl as a parameter in hieradata for this service.",
-				CheckOutput:      "Connectivity to neo4j is ok",
-				LastUpdated:      testSuccessTime,
-			},
+		{
+			name:                  "SuccessWithConceptType",
+			req:                   newRequest("GET", "/sixdegrees/mostMentionedPeople?type=organisation", "application/json", nil),
+			driver:                &dummyDriver{},
+			statusCode:            http.StatusOK,
+			contentType:           "",
+			body:                  "[]",
+			expectedResultLimit:   defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedConceptType:   "Organisation",
 		},
-	}
-
-	testErrorTimeStr := "2016-12-13T16:03:31.2387546+02:00"
-	testErrorTime, _ := time.Parse(timeTemplate, testErrorTimeStr)
-
+		{
+			name:        "FailureWithUnknownConceptType",
+			req:         newRequest("GET", "/sixdegrees/mostMentionedPeople?type=Content", "application/json", nil),
+			driver:      &dummyDriver{},
+			statusCode:  http.StatusBadRequest,
+			contentType: "",
+			body:        message("Error converting type query param, err=unknown concept type Content, must be one of Person, Organisation, Topic, Location, Brand, Genre"),
+		},
+		{
+			name:                  "SuccessWithMostMentionedConcepts",
+			req:                   newRequest("GET", "/sixdegrees/mostMentionedConcepts?type=Topic", "application/json", nil),
+			driver:                &dummyDriver{mentionedThings: getMentionedThings()},
+			statusCode:            http.StatusOK,
+			contentType:           "",
+			body:                  `[{"id": "http://api.ft.com/things/12345", "apiUrl": "http://api.ft.com/people/12345", "prefLabel": "Test Person", "mentions": 7, "contentCount": 6, "score": 7}]`,
+			expectedResultLimit:   defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedConceptType:   "Topic",
+		},
+		{
+			name:        "FailureWithMostMentionedConceptsWithoutType",
+			req:         newRequest("GET", "/sixdegrees/mostMentionedConcepts", "application/json", nil),
+			driver:      &dummyDriver{},
+			statusCode:  http.StatusBadRequest,
+			contentType: "",
+			body:        message("Error converting type query param, err=type is required"),
+		},
+		{
+			name:                  "SuccessWithoutMentionCounts",
+			req:                   newRequest("GET", "/sixdegrees/mostMentionedPeople", "application/json", nil),
+			driver:                &dummyDriver{mentionedThings: getMentionedThings()},
+			statusCode:            http.StatusOK,
+			contentType:           "",
+			body:                  `[{"id": "http://api.ft.com/things/12345", "apiUrl": "http://api.ft.com/people/12345", "prefLabel": "Test Person"}]`,
+			expectedResultLimit:   defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+		},
+		{
+			name:                  "SuccessV2WithMentionCounts",
+			req:                   newRequest("GET", "/sixdegrees/v2/mostMentionedPeople?limit=5", "application/json", nil),
+			driver:                &dummyDriver{mentionedThings: getMentionedThings()},
+			statusCode:            http.StatusOK,
+			contentType:           "",
+			body:                  `[{"id": "http://api.ft.com/things/12345", "apiUrl": "http://api.ft.com/people/12345", "prefLabel": "Test Person", "mentions": 7, "contentCount": 6, "score": 7}]`,
+			expectedResultLimit:   5,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+		},
+		{
+			name:        "FailureV2WithInvalidResultLimit",
+			req:         newRequest("GET", "/sixdegrees/v2/mostMentionedPeople?limit=FAIL", "application/json", nil),
+			driver:      &dummyDriver{},
+			statusCode:  http.StatusBadRequest,
+			contentType: "",
+			body:        message("Error converting limit query param, err=strconv.Atoi: parsing \\\"FAIL\\\": invalid syntax"),
+		},
+		{
+			name:                  "NotFoundV2",
+			req:                   newRequest("GET", "/sixdegrees/v2/mostMentionedPeople", "application/json", nil),
+			driver:                &dummyDriver{shouldReturnNotFound: true},
+			statusCode:            http.StatusNotFound,
+			contentType:           "",
+			body:                  message("No result"),
+			expectedResultLimit:   defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(test.expectedResultLimit, test.driver.argLimit, fmt.Sprintf("%s: Wrong limit", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+		if test.expectedConceptType != "" {
+			assert.Equal(test.expectedConceptType, test.driver.argConceptType, fmt.Sprintf("%s: Wrong concept type", test.name))
+		}
+		if test.expectedFilter.Weighting != "" {
+			assert.Equal(test.expectedFilter, test.driver.argFilter, fmt.Sprintf("%s: Wrong mention filter", test.name))
+		}
+	}
+}
+
+func TestGetConnectedConcepts(t *testing.T) {
+	assert := assert.New(t)
+	tests := []handlerTestCase{
+		{
+			name:                       "Success",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedConcepts?uuid=%s&type=Organisation", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       "[]",
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedConceptType:        "Organisation",
+			expectedFilter:             MentionFilter{Predicates: []string{"mentions"}, Weighting: defaultWeighting},
+		},
+		{
+			name:                       "SuccessV2SortsNewestContentFirst",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/v2/connectedConcepts?uuid=%s&type=Organisation", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       "[]",
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedConceptType:        "Organisation",
+			expectedFilter:             MentionFilter{Predicates: []string{"mentions"}, Weighting: defaultWeighting},
+			expectedContentSort:        "newest",
+		},
+		{
+			name:       "FailureWithUnknownContentSort",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedConcepts?uuid=%s&type=Organisation&contentSort=c.uuid", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting contentSort query param, err=unknown content sort c.uuid, must be one of newest, oldest, relevance"),
+		},
+		{
+			name:       "FailureWithoutType",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedConcepts?uuid=%s", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting type query param, err=type is required"),
+		},
+		{
+			name:       "FailureWithUnknownType",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedConcepts?uuid=%s&type=Thing)--(x", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting type query param, err=unknown concept type Thing)--(x, must be one of Person, Organisation, Topic, Location, Brand, Genre"),
+		},
+		{
+			name:                       "NotFound",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedConcepts?uuid=%s&type=location", "99999"), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusNotFound,
+			body:                       message("No concept found with uuid 99999"),
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedConceptType:        "Location",
+			expectedFilter:             MentionFilter{Predicates: []string{"mentions"}, Weighting: defaultWeighting},
+		},
+		{
+			name:                       "ReadError",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/connectedConcepts?uuid=%s&type=Person", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID, shouldFail: true},
+			statusCode:                 http.StatusInternalServerError,
+			body:                       message("Error retrieving result for 12345, err=TEST failing to READ"),
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedContentLimit:       defaultContentLimit,
+			expectedConceptType:        "Person",
+			expectedFilter:             MentionFilter{Predicates: []string{"mentions"}, Weighting: defaultWeighting},
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(test.expectedResultLimit, test.driver.argLimit, fmt.Sprintf("%s: Wrong limit", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+		assert.Equal(test.expectedMinimumConnections, test.driver.argMinimumConnections, fmt.Sprintf("%s: Wrong minimum connections", test.name))
+		assert.Equal(test.expectedContentLimit, test.driver.argContentLimit, fmt.Sprintf("%s: Wrong content limit", test.name))
+		assert.Equal(test.expectedConceptType, test.driver.argConceptType, fmt.Sprintf("%s: Wrong concept type", test.name))
+		assert.Equal(test.expectedContentSort, test.driver.argContentSort, fmt.Sprintf("%s: Wrong content sort", test.name))
+		assert.Equal(test.expectedFilter, test.driver.argFilter, fmt.Sprintf("%s: Wrong mention filter", test.name))
+	}
+}
+
+func TestGetShortestPath(t *testing.T) {
+	assert := assert.New(t)
+	tests := []handlerTestCase{
+		{
+			name:                  "Success",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/path?from=%s&to=%s", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID},
+			statusCode:            http.StatusOK,
+			body:                  `{"people": [], "hops": []}`,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedContentLimit:  defaultContentLimit,
+			expectedMaxHops:       defaultMaxHops,
+		},
+		{
+			name:                  "SuccessWithMaxHopsAndContentLimit",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/path?from=%s&to=%s&maxHops=2&contentLimit=1", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID},
+			statusCode:            http.StatusOK,
+			body:                  `{"people": [], "hops": []}`,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedContentLimit:  1,
+			expectedMaxHops:       2,
+		},
+		{
+			name:       "FailureWithMissingTo",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/path?from=%s", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message(fmt.Sprintf("Two different person uuids are required: from=%s, to=", knownUUID)),
+		},
+		{
+			name:       "FailureWithSameFromAndTo",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/path?from=%s&to=%s", knownUUID, knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message(fmt.Sprintf("Two different person uuids are required: from=%s, to=%s", knownUUID, knownUUID)),
+		},
+		{
+			name:       "FailureWithInvalidMaxHops",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/path?from=%s&to=%s&maxHops=FAIL", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting maxHops query param, err=strconv.Atoi: parsing \\\"FAIL\\\": invalid syntax"),
+		},
+		{
+			name:       "FailureWithTooManyMaxHops",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/path?from=%s&to=%s&maxHops=7", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting maxHops query param, err=maxHops must be between 1 and 6"),
+		},
+		{
+			name:                  "NotFound",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/path?from=%s&to=%s", "99999", otherKnownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID},
+			statusCode:            http.StatusNotFound,
+			body:                  message(fmt.Sprintf("No path found between people with uuids 99999 and %s", otherKnownUUID)),
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedContentLimit:  defaultContentLimit,
+			expectedMaxHops:       defaultMaxHops,
+		},
+		{
+			name:                  "ReadError",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/path?from=%s&to=%s", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID, shouldFail: true},
+			statusCode:            http.StatusInternalServerError,
+			body:                  message(fmt.Sprintf("Error retrieving path between %s and %s, err=TEST failing to READ", knownUUID, otherKnownUUID)),
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedContentLimit:  defaultContentLimit,
+			expectedMaxHops:       defaultMaxHops,
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+		assert.Equal(test.expectedContentLimit, test.driver.argContentLimit, fmt.Sprintf("%s: Wrong content limit", test.name))
+		assert.Equal(test.expectedMaxHops, test.driver.argMaxHops, fmt.Sprintf("%s: Wrong max hops", test.name))
+	}
+}
+
+func TestGetConnection(t *testing.T) {
+	assert := assert.New(t)
+	tests := []handlerTestCase{
+		{
+			name:                  "Success",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/connection?uuid1=%s&uuid2=%s", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID},
+			statusCode:            http.StatusOK,
+			body:                  `{"people": [], "count": 0, "content": [], "histogram": []}`,
+			expectedResultLimit:   defaultConnectionResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+		},
+		{
+			name:                  "SuccessWithDatesAndLimit",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/connection?uuid1=%s&uuid2=%s&fromDate=2017-05-01&toDate=2017-05-08&limit=5", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID},
+			statusCode:            http.StatusOK,
+			body:                  `{"people": [], "count": 0, "content": [], "histogram": []}`,
+			expectedResultLimit:   5,
+			expectedFromDateEpoch: time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC).Unix(),
+			expectedToDateEpoch:   time.Date(2017, 5, 8, 0, 0, 0, 0, time.UTC).Unix(),
+		},
+		{
+			name:       "FailureWithMissingUUID2",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connection?uuid1=%s", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message(fmt.Sprintf("Two different person uuids are required: uuid1=%s, uuid2=", knownUUID)),
+		},
+		{
+			name:       "FailureWithSameUUIDs",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connection?uuid1=%s&uuid2=%s", knownUUID, knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message(fmt.Sprintf("Two different person uuids are required: uuid1=%s, uuid2=%s", knownUUID, knownUUID)),
+		},
+		{
+			name:       "FailureWithInvalidLimit",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/connection?uuid1=%s&uuid2=%s&limit=FAIL", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting limit query param, err=strconv.Atoi: parsing \\\"FAIL\\\": invalid syntax"),
+		},
+		{
+			name:                  "NotFound",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/connection?uuid1=%s&uuid2=%s", "99999", otherKnownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID},
+			statusCode:            http.StatusNotFound,
+			body:                  message(fmt.Sprintf("No people found with uuids 99999 and %s", otherKnownUUID)),
+			expectedResultLimit:   defaultConnectionResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+		},
+		{
+			name:                  "ReadError",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/connection?uuid1=%s&uuid2=%s", knownUUID, otherKnownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID, shouldFail: true},
+			statusCode:            http.StatusInternalServerError,
+			body:                  message(fmt.Sprintf("Error retrieving connection between %s and %s, err=TEST failing to READ", knownUUID, otherKnownUUID)),
+			expectedResultLimit:   defaultConnectionResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(test.expectedResultLimit, test.driver.argLimit, fmt.Sprintf("%s: Wrong result limit", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+	}
+}
+
+func TestGetConnectionPagination(t *testing.T) {
+	assert := assert.New(t)
+	content := []Content{
+		{ID: "c2", Title: "Newer", PublishedDate: "2017-05-03T10:00:00Z"},
+		{ID: "c1", Title: "Older", PublishedDate: "2017-05-02T10:00:00Z"},
+	}
+	driver := &dummyDriver{contentUUID: knownUUID, connectionContent: content}
+	router := mux.NewRouter()
+	NewHandler(driver, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("GET", fmt.Sprintf("/sixdegrees/connection?uuid1=%s&uuid2=%s&limit=1&fromDate=2017-05-01&toDate=2017-05-08", knownUUID, otherKnownUUID), "application/json", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.JSONEq(`{"people": [], "count": 2, "content": [{"id": "c2", "title": "Newer", "publishedDate": "2017-05-03T10:00:00Z"}], "histogram": []}`, rec.Body.String())
+	assert.Equal("2017-05-01T00:00:00Z", rec.Header().Get("X-From-Date"))
+
+	link := rec.Header().Get("Link")
+	require.True(t, strings.HasPrefix(link, "</sixdegrees/connection?cursor="), "Wrong next link %s", link)
+	next := strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`)
+
+	driver.connectionContent = content[1:]
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("GET", next, "application/json", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Empty(rec.Header().Get("Link"), "Last page should not link to a next one")
+	published := time.Date(2017, 5, 3, 10, 0, 0, 0, time.UTC).Unix()
+	assert.Equal(&PageKey{Score: float64(published), UUID: "c2"}, driver.argAfter, "Next page should start after the last content")
+	assert.Equal(time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC).Unix(), driver.argFromDateEpoch, "Next page should keep the window of the cursor")
+}
+
+func TestGetNetwork(t *testing.T) {
+	assert := assert.New(t)
+	tests := []handlerTestCase{
+		{
+			name:                       "Success",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       `{"nodes": [], "edges": []}`,
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedDepth:              defaultNetworkDepth,
+		},
+		{
+			name:                       "SuccessWithDepthAndResultLimitAndMinimumConnections",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s&depth=3&limit=5&minimumConnections=2", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusOK,
+			body:                       `{"nodes": [], "edges": []}`,
+			expectedResultLimit:        5,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: 2,
+			expectedDepth:              3,
+		},
+		{
+			name:       "FailureWithInvalidDepth",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s&depth=FAIL", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting depth query param, err=strconv.Atoi: parsing \\\"FAIL\\\": invalid syntax"),
+		},
+		{
+			name:       "FailureWithUnknownFormat",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s&format=csv", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting format query param, must be one of json, graphml, gexf, dot, cytoscape: format=csv"),
+		},
+		{
+			name:       "FailureWithTooDeepDepth",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s&depth=4", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting depth query param, err=depth must be between 1 and 3"),
+		},
+		{
+			name:       "FailureWithZeroDepth",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s&depth=0", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting depth query param, err=depth must be between 1 and 3"),
+		},
+		{
+			name:       "FailureWithNegativeMinConnections",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s&minimumConnections=-1", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting minimumConnections query param, err=minimumConnections must not be negative"),
+		},
+		{
+			name:       "FailureWithZeroResultLimit",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s&limit=0", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting limit query param, err=limit must be between 1 and 100"),
+		},
+		{
+			name:       "FailureWithTooLargeResultLimit",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s&limit=101", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting limit query param, err=limit must be between 1 and 100"),
+		},
+		{
+			name:       "FailureWithInvalidMinConnections",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s&minimumConnections=FAIL", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting minimumConnections query param, err=strconv.Atoi: parsing \\\"FAIL\\\": invalid syntax"),
+		},
+		{
+			name:                       "NotFound",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s", "99999"), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID},
+			statusCode:                 http.StatusNotFound,
+			body:                       message("No network found for person with uuid 99999"),
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedDepth:              defaultNetworkDepth,
+		},
+		{
+			name:                       "ReadError",
+			req:                        newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s", knownUUID), "application/json", nil),
+			driver:                     &dummyDriver{contentUUID: knownUUID, shouldFail: true},
+			statusCode:                 http.StatusInternalServerError,
+			body:                       message("Error retrieving network for 12345, err=TEST failing to READ"),
+			expectedResultLimit:        defaultConnectedPeopleResultLimit,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedDepth:              defaultNetworkDepth,
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(test.expectedResultLimit, test.driver.argLimit, fmt.Sprintf("%s: Wrong limit", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+		assert.Equal(test.expectedMinimumConnections, test.driver.argMinimumConnections, fmt.Sprintf("%s: Wrong minimum connections", test.name))
+		assert.Equal(test.expectedDepth, test.driver.argDepth, fmt.Sprintf("%s: Wrong depth", test.name))
+	}
+}
+
+func TestGetNetworkFormats(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		name                string
+		path                string
+		accept              string
+		expectedContentType string
+		expectedBody        string
+	}{
+		{"DefaultsToJSON", "", "*/*", "application/json; charset=UTF-8", "{\"nodes\":[],\"edges\":[]}\n"},
+		{"GraphMLFormat", "&format=graphml", "", "application/graphml+xml; charset=UTF-8", "<graphml"},
+		{"GEXFFormat", "&format=gexf", "", "application/gexf+xml; charset=UTF-8", "<gexf"},
+		{"DOTFormat", "&format=dot", "", "text/vnd.graphviz; charset=UTF-8", "graph network {\n}\n"},
+		{"CytoscapeFormat", "&format=cytoscape", "", "application/json; charset=UTF-8", "{\"elements\":{\"nodes\":[],\"edges\":[]}}\n"},
+		{"GraphMLAccepted", "", "application/graphml+xml", "application/graphml+xml; charset=UTF-8", "<graphml"},
+		{"DOTAcceptedWithQuality", "", "text/html;q=0.9, text/vnd.graphviz;q=0.8", "text/vnd.graphviz; charset=UTF-8", "graph network {"},
+		{"FormatOverridesAccept", "&format=gexf", "application/graphml+xml", "application/gexf+xml; charset=UTF-8", "<gexf"},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		NewHandler(&dummyDriver{contentUUID: knownUUID}, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+		req := newRequest("GET", fmt.Sprintf("/sixdegrees/network?uuid=%s%s", knownUUID, test.path), "application/json", nil)
+		req.Header.Set("Accept", test.accept)
+		router.ServeHTTP(rec, req)
+		assert.Equal(http.StatusOK, rec.Code, fmt.Sprintf("%s: Wrong response code", test.name))
+		assert.Equal(test.expectedContentType, rec.Header().Get("Content-Type"), fmt.Sprintf("%s: Wrong content type", test.name))
+		assert.Contains(rec.Body.String(), test.expectedBody, fmt.Sprintf("%s: Wrong body", test.name))
+	}
+}
+
+func TestRedirectToCanonicalUUID(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		name             string
+		url              string
+		canonicalUUIDs   map[string]string
+		statusCode       int
+		expectedLocation string
+		body             string
+	}{
+		{
+			name:             "ConnectedPeopleBySourceUUID",
+			url:              "/sixdegrees/connectedPeople?uuid=source&limit=5",
+			canonicalUUIDs:   map[string]string{"source": knownUUID},
+			statusCode:       http.StatusMovedPermanently,
+			expectedLocation: "/sixdegrees/connectedPeople?limit=5&uuid=12345",
+			body:             message("source identifies the concept with uuid 12345"),
+		},
+		{
+			name:             "V2ConnectedConceptsByFactSetIdentifier",
+			url:              "/sixdegrees/v2/connectedConcepts?uuid=91ABCD-F&type=Organisation",
+			canonicalUUIDs:   map[string]string{"91ABCD-F": knownUUID},
+			statusCode:       http.StatusMovedPermanently,
+			expectedLocation: "/sixdegrees/v2/connectedConcepts?type=Organisation&uuid=12345",
+			body:             message("91ABCD-F identifies the concept with uuid 12345"),
+		},
+		{
+			name:             "NetworkByTMEIdentifier",
+			url:              "/sixdegrees/network?uuid=TME-ID&depth=1",
+			canonicalUUIDs:   map[string]string{"TME-ID": knownUUID},
+			statusCode:       http.StatusMovedPermanently,
+			expectedLocation: "/sixdegrees/network?depth=1&uuid=12345",
+			body:             message("TME-ID identifies the concept with uuid 12345"),
+		},
+		{
+			name:           "NotFoundWhenCanonicalAlready",
+			url:            "/sixdegrees/connectedPeople?uuid=99999",
+			canonicalUUIDs: map[string]string{"99999": "99999"},
+			statusCode:     http.StatusNotFound,
+			body:           message("No person found with uuid 99999"),
+		},
+		{
+			name:       "NotFoundWhenUnknown",
+			url:        "/sixdegrees/connectedPeople?uuid=unknown",
+			statusCode: http.StatusNotFound,
+			body:       message("No person found with uuid unknown"),
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		NewHandler(&dummyDriver{contentUUID: knownUUID, canonicalUUIDs: test.canonicalUUIDs}, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+		router.ServeHTTP(rec, newRequest("GET", test.url, "application/json", nil))
+		assert.Equal(test.statusCode, rec.Code, fmt.Sprintf("%s: Wrong response code", test.name))
+		assert.Equal(test.expectedLocation, rec.Header().Get("Location"), fmt.Sprintf("%s: Wrong location", test.name))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+	}
+}
+
+func TestCursorPaginationKeepsScores(t *testing.T) {
+	assert := assert.New(t)
+	people := getConnectedPeople()
+	people = append(people, ConnectedPerson{
+		Person:  Thing{ID: "http://api.ft.com/things/13579", PrefLabel: "Third Person"},
+		Count:   2,
+		Content: people[0].Content,
+	})
+	driver := &dummyDriver{contentUUID: knownUUID, connectedPeople: people}
+	router := mux.NewRouter()
+	NewHandler(driver, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("GET", fmt.Sprintf("/sixdegrees/connectedPeople?uuid=%s&limit=1&weighting=relevance", knownUUID), "application/json", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.JSONEq(`[{"person": {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person"}, "count": 5, "score": 5, "content": [{"id": "c1", "apiUrl": "http://api.ft.com/content/c1", "title": "Some Title"}]}]`, rec.Body.String())
+
+	link := rec.Header().Get("Link")
+	require.True(t, strings.HasPrefix(link, "</sixdegrees/connectedPeople?cursor="), "Wrong next link %s", link)
+	next := strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`)
+
+	driver.connectedPeople = people[1:]
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("GET", next, "application/json", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.JSONEq(`[{"person": {"id": "http://api.ft.com/things/13579", "prefLabel": "Third Person"}, "count": 2, "score": 0, "content": [{"id": "c1", "apiUrl": "http://api.ft.com/content/c1", "title": "Some Title"}]}]`, rec.Body.String(), "Next pages of weighted connections should keep their scores, zero ones included")
+}
+
+func TestCursorPagination(t *testing.T) {
+	assert := assert.New(t)
+	twoThings := append(getMentionedThings(), MentionedThing{
+		Thing:    Thing{ID: "http://api.ft.com/things/67890", APIURL: "http://api.ft.com/people/67890", PrefLabel: "Other Person"},
+		Mentions: 3,
+		Score:    3,
+	})
+	driver := &dummyDriver{mentionedThings: twoThings}
+	router := mux.NewRouter()
+	NewHandler(driver, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("GET", "/sixdegrees/v2/mostMentionedPeople?limit=1&fromDate=2017-05-01&toDate=2017-05-08&weighting=relevance", "application/json", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.JSONEq(`[{"id": "http://api.ft.com/things/12345", "apiUrl": "http://api.ft.com/people/12345", "prefLabel": "Test Person", "mentions": 7, "contentCount": 6, "score": 7}]`, rec.Body.String())
+	assert.Nil(driver.argAfter, "First page should not start after anything")
+	firstFromDateEpoch := driver.argFromDateEpoch
+
+	link := rec.Header().Get("Link")
+	require.True(t, strings.HasPrefix(link, "</sixdegrees/v2/mostMentionedPeople?cursor=") && strings.HasSuffix(link, `&limit=1>; rel="next"`), "Wrong next link %s", link)
+	next := strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`)
+
+	driver.mentionedThings = twoThings[1:]
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, newRequest("GET", next, "application/json", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Empty(rec.Header().Get("Link"), "Last page should not link to a next one")
+	assert.Equal(&PageKey{Score: 7, Count: 7, UUID: "12345"}, driver.argAfter, "Next page should start after the last result")
+	assert.Equal(1, driver.argLimit)
+	assert.Equal(firstFromDateEpoch, driver.argFromDateEpoch, "Next page should keep the window of the cursor")
+	assert.Equal("relevance", driver.argFilter.Weighting, "Next page should keep the filters of the cursor")
+
+	cursorParam := next[strings.Index(next, "cursor=")+len("cursor=") : strings.Index(next, "&")]
+	for _, test := range []struct {
+		name    string
+		path    string
+		message string
+	}{
+		{"TamperedCursor", "/sixdegrees/v2/mostMentionedPeople?cursor=e30." + cursorParam[strings.Index(cursorParam, ".")+1:], "Error converting cursor query param, err=invalid cursor signature"},
+		{"MalformedCursor", "/sixdegrees/v2/mostMentionedPeople?cursor=FAIL", "Error converting cursor query param, err=malformed cursor"},
+		{"CursorOfAnotherEndpoint", "/sixdegrees/mostMentionedConcepts?type=Person&cursor=" + cursorParam, "Error converting cursor query param, err=cursor is for /sixdegrees/v2/mostMentionedPeople"},
+		{"CursorOfAnotherSecret", "/sixdegrees/connectedPeople?uuid=12345&cursor=" + cursorSigner{[]byte("other secret")}.sign(cursor{Query: cursorQuery{Path: "/sixdegrees/connectedPeople"}}), "Error converting cursor query param, err=invalid cursor signature"},
+	} {
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, newRequest("GET", test.path, "application/json", nil))
+		assert.Equal(http.StatusBadRequest, rec.Code, fmt.Sprintf("%s: Wrong response code", test.name))
+		assert.JSONEq(message(test.message), rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+	}
+}
+
+func TestResultLimits(t *testing.T) {
+	assert := assert.New(t)
+	driver := &dummyDriver{contentUUID: knownUUID, connectedPeople: getConnectedPeople(), mentionedThings: getMentionedThings()}
+	router := mux.NewRouter()
+	NewHandler(driver, "max-age=360, public", testCursorSecret, QueryTimeouts{}).RegisterHandlers(router)
+
+	for _, path := range []string{
+		"/sixdegrees/mostMentionedPeople?",
+		"/sixdegrees/v2/mostMentionedPeople?",
+		"/sixdegrees/connectedPeople?uuid=" + knownUUID + "&",
+		"/sixdegrees/connectedConcepts?uuid=" + knownUUID + "&type=Person&",
+		"/sixdegrees/connection?uuid1=" + knownUUID + "&uuid2=" + otherKnownUUID + "&",
+		"/sixdegrees/trendingPeople?",
+	} {
+		for _, limit := range []string{"0", "-1", "1001"} {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, newRequest("GET", path+"limit="+limit, "application/json", nil))
+			assert.Equal(http.StatusBadRequest, rec.Code, fmt.Sprintf("%slimit=%s: Wrong response code", path, limit))
+			assert.JSONEq(message("Error converting limit query param, err=limit must be between 1 and 1000"), rec.Body.String(), fmt.Sprintf("%slimit=%s: Wrong body", path, limit))
+		}
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, newRequest("GET", path+"limit=1", "application/json", nil))
+		assert.Equal(http.StatusOK, rec.Code, fmt.Sprintf("%slimit=1: Wrong response code", path))
+	}
+}
+
+func TestGetTrendingPeople(t *testing.T) {
+	assert := assert.New(t)
+	tests := []handlerTestCase{
+		{
+			name:                      "Success",
+			req:                       newRequest("GET", "/sixdegrees/trendingPeople", "application/json", nil),
+			driver:                    &dummyDriver{},
+			statusCode:                http.StatusOK,
+			body:                      "[]",
+			expectedResultLimit:       defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch:     getDefaultFromDate().Unix(),
+			expectedToDateEpoch:       getDefaultToDate().Unix(),
+			expectedPreviousFromEpoch: time.Now().AddDate(0, 0, -14).Unix(),
+			expectedPreviousToEpoch:   getDefaultFromDate().Unix(),
+			expectedConceptType:       "Person",
+			expectedRankBy:            "absolute",
+		},
+		{
+			name:                      "SuccessWithRelativeRankingAndType",
+			req:                       newRequest("GET", "/sixdegrees/trendingPeople?rankBy=relative&type=organisation&limit=5", "application/json", nil),
+			driver:                    &dummyDriver{},
+			statusCode:                http.StatusOK,
+			body:                      "[]",
+			expectedResultLimit:       5,
+			expectedFromDateEpoch:     getDefaultFromDate().Unix(),
+			expectedToDateEpoch:       getDefaultToDate().Unix(),
+			expectedPreviousFromEpoch: time.Now().AddDate(0, 0, -14).Unix(),
+			expectedPreviousToEpoch:   getDefaultFromDate().Unix(),
+			expectedConceptType:       "Organisation",
+			expectedRankBy:            "relative",
+		},
+		{
+			name:                      "SuccessWithPreviousPeriodOfSameLength",
+			req:                       newRequest("GET", "/sixdegrees/trendingPeople?fromDate=2017-01-10&toDate=2017-01-20", "application/json", nil),
+			driver:                    &dummyDriver{},
+			statusCode:                http.StatusOK,
+			body:                      "[]",
+			expectedResultLimit:       defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch:     time.Date(2017, 1, 10, 0, 0, 0, 0, time.UTC).Unix(),
+			expectedToDateEpoch:       time.Date(2017, 1, 20, 0, 0, 0, 0, time.UTC).Unix(),
+			expectedPreviousFromEpoch: time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC).Unix(),
+			expectedPreviousToEpoch:   time.Date(2017, 1, 10, 0, 0, 0, 0, time.UTC).Unix(),
+			expectedConceptType:       "Person",
+			expectedRankBy:            "absolute",
+		},
+		{
+			name:                      "SuccessWithFromDateLaterThanToDate",
+			req:                       newRequest("GET", fmt.Sprintf("/sixdegrees/trendingPeople?fromDate=%s&toDate=%s", time.Now().AddDate(0, 0, 1).Format("2006-01-02"), time.Now().AddDate(0, 0, -1).Format("2006-01-02")), "application/json", nil),
+			driver:                    &dummyDriver{},
+			statusCode:                http.StatusOK,
+			body:                      "[]",
+			expectedResultLimit:       defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch:     time.Now().AddDate(0, 0, -8).Unix(),
+			expectedToDateEpoch:       time.Now().AddDate(0, 0, -1).Unix(),
+			expectedPreviousFromEpoch: time.Now().AddDate(0, 0, -15).Unix(),
+			expectedPreviousToEpoch:   time.Now().AddDate(0, 0, -8).Unix(),
+			expectedConceptType:       "Person",
+			expectedRankBy:            "absolute",
+		},
+		{
+			name:       "FailureWithInvalidRankBy",
+			req:        newRequest("GET", "/sixdegrees/trendingPeople?rankBy=FAIL", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting rankBy query param, must be absolute or relative: rankBy=FAIL"),
+		},
+		{
+			name:       "FailureWithUnknownType",
+			req:        newRequest("GET", "/sixdegrees/trendingPeople?type=FAIL", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting type query param, err=unknown concept type FAIL, must be one of Person, Organisation, Topic, Location, Brand, Genre"),
+		},
+		{
+			name:       "FailureWithInvalidResultLimit",
+			req:        newRequest("GET", "/sixdegrees/trendingPeople?limit=FAIL", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting limit query param, err=strconv.Atoi: parsing \\\"FAIL\\\": invalid syntax"),
+		},
+		{
+			name:                      "NotFound",
+			req:                       newRequest("GET", "/sixdegrees/trendingPeople", "application/json", nil),
+			driver:                    &dummyDriver{shouldReturnNotFound: true},
+			statusCode:                http.StatusNotFound,
+			body:                      message("No result"),
+			expectedResultLimit:       defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch:     getDefaultFromDate().Unix(),
+			expectedToDateEpoch:       getDefaultToDate().Unix(),
+			expectedPreviousFromEpoch: time.Now().AddDate(0, 0, -14).Unix(),
+			expectedPreviousToEpoch:   getDefaultFromDate().Unix(),
+			expectedConceptType:       "Person",
+			expectedRankBy:            "absolute",
+		},
+		{
+			name:                      "ReadError",
+			req:                       newRequest("GET", "/sixdegrees/trendingPeople", "application/json", nil),
+			driver:                    &dummyDriver{shouldFail: true},
+			statusCode:                http.StatusInternalServerError,
+			body:                      message("Error retrieving result from DB"),
+			expectedResultLimit:       defaultMostMentionedPeopleResultLimit,
+			expectedFromDateEpoch:     getDefaultFromDate().Unix(),
+			expectedToDateEpoch:       getDefaultToDate().Unix(),
+			expectedPreviousFromEpoch: time.Now().AddDate(0, 0, -14).Unix(),
+			expectedPreviousToEpoch:   getDefaultFromDate().Unix(),
+			expectedConceptType:       "Person",
+			expectedRankBy:            "absolute",
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(test.expectedResultLimit, test.driver.argLimit, fmt.Sprintf("%s: Wrong limit", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+		assert.Equal(time.Unix(test.expectedPreviousFromEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argPreviousFromEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong previous from date", test.name))
+		assert.Equal(time.Unix(test.expectedPreviousToEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argPreviousToEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong previous to date", test.name))
+		assert.Equal(test.expectedConceptType, test.driver.argConceptType, fmt.Sprintf("%s: Wrong concept type", test.name))
+		assert.Equal(test.expectedRankBy, test.driver.argRankBy, fmt.Sprintf("%s: Wrong ranking", test.name))
+	}
+}
+
+func TestGetMentions(t *testing.T) {
+	assert := assert.New(t)
+	tests := []handlerTestCase{
+		{
+			name:                  "Success",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/people/%s/mentions", knownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID},
+			statusCode:            http.StatusOK,
+			body:                  `[{"date": "2017-01-09", "count": 2}, {"date": "2017-01-16", "count": 0}]`,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedInterval:      "day",
+		},
+		{
+			name:                  "SuccessWithIntervalAndPeriod",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/people/%s/mentions?interval=week&fromDate=2017-01-10&toDate=2017-01-20", knownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID},
+			statusCode:            http.StatusOK,
+			body:                  `[{"date": "2017-01-09", "count": 2}, {"date": "2017-01-16", "count": 0}]`,
+			expectedFromDateEpoch: time.Date(2017, 1, 10, 0, 0, 0, 0, time.UTC).Unix(),
+			expectedToDateEpoch:   time.Date(2017, 1, 20, 0, 0, 0, 0, time.UTC).Unix(),
+			expectedInterval:      "week",
+		},
+		{
+			name:       "FailureWithInvalidInterval",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/people/%s/mentions?interval=year", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting interval query param, must be one of day, week, month: interval=year"),
+		},
+		{
+			name:       "FailureWithInvalidFromDate",
+			req:        newRequest("GET", fmt.Sprintf("/sixdegrees/people/%s/mentions?fromDate=FAIL", knownUUID), "application/json", nil),
+			driver:     &dummyDriver{contentUUID: knownUUID},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting toDate or fromDate query params: fromDate=FAIL, toDate="),
+		},
+		{
+			name:                  "NotFound",
+			req:                   newRequest("GET", "/sixdegrees/people/99999/mentions", "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID},
+			statusCode:            http.StatusNotFound,
+			body:                  message("No person found with uuid 99999"),
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedInterval:      "day",
+		},
+		{
+			name:                  "ReadError",
+			req:                   newRequest("GET", fmt.Sprintf("/sixdegrees/people/%s/mentions", knownUUID), "application/json", nil),
+			driver:                &dummyDriver{contentUUID: knownUUID, shouldFail: true},
+			statusCode:            http.StatusInternalServerError,
+			body:                  message("Error retrieving mentions for 12345, err=TEST failing to READ"),
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedInterval:      "day",
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+		assert.Equal(test.expectedInterval, test.driver.argInterval, fmt.Sprintf("%s: Wrong interval", test.name))
+	}
+}
+
+func TestGetPeopleSearch(t *testing.T) {
+	assert := assert.New(t)
+	tests := []handlerTestCase{
+		{
+			name:                  "Success",
+			req:                   newRequest("GET", "/sixdegrees/people/search?q=Test", "application/json", nil),
+			driver:                &dummyDriver{},
+			statusCode:            http.StatusOK,
+			body:                  `[{"id": "http://api.ft.com/things/12345", "prefLabel": "Test Person", "match": "Test Person", "mentions": 4}]`,
+			expectedResultLimit:   defaultPeopleSearchResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedQuery:         "Test",
+		},
+		{
+			name:                  "SuccessWithLimitAndPeriod",
+			req:                   newRequest("GET", "/sixdegrees/people/search?q=%20test%20pe&limit=5&fromDate=2017-01-10&toDate=2017-01-20", "application/json", nil),
+			driver:                &dummyDriver{},
+			statusCode:            http.StatusOK,
+			body:                  `[{"id": "http://api.ft.com/things/12345", "prefLabel": "Test Person", "match": "Test Person", "mentions": 4}]`,
+			expectedResultLimit:   5,
+			expectedFromDateEpoch: time.Date(2017, 1, 10, 0, 0, 0, 0, time.UTC).Unix(),
+			expectedToDateEpoch:   time.Date(2017, 1, 20, 0, 0, 0, 0, time.UTC).Unix(),
+			expectedQuery:         "test pe",
+		},
+		{
+			name:                  "SuccessWithoutMatches",
+			req:                   newRequest("GET", "/sixdegrees/people/search?q=nobody", "application/json", nil),
+			driver:                &dummyDriver{},
+			statusCode:            http.StatusOK,
+			body:                  "[]",
+			expectedResultLimit:   defaultPeopleSearchResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedQuery:         "nobody",
+		},
+		{
+			name:       "FailureWithoutQuery",
+			req:        newRequest("GET", "/sixdegrees/people/search?q=%20", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting q query param, err=q is required"),
+		},
+		{
+			name:       "FailureWithTooShortQuery",
+			req:        newRequest("GET", "/sixdegrees/people/search?q=%20bo%20", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting q query param, err=q must be at least 3 characters"),
+		},
+		{
+			name:       "FailureWithLimitOutOfRange",
+			req:        newRequest("GET", "/sixdegrees/people/search?q=test&limit=51", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting limit query param, err=limit must be between 1 and 50"),
+		},
+		{
+			name:                  "ReadError",
+			req:                   newRequest("GET", "/sixdegrees/people/search?q=test", "application/json", nil),
+			driver:                &dummyDriver{shouldFail: true},
+			statusCode:            http.StatusInternalServerError,
+			body:                  message("Error retrieving result from DB"),
+			expectedResultLimit:   defaultPeopleSearchResultLimit,
+			expectedFromDateEpoch: getDefaultFromDate().Unix(),
+			expectedToDateEpoch:   getDefaultToDate().Unix(),
+			expectedQuery:         "test",
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(test.expectedQuery, test.driver.argQuery, fmt.Sprintf("%s: Wrong query", test.name))
+		assert.Equal(test.expectedResultLimit, test.driver.argLimit, fmt.Sprintf("%s: Wrong limit", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+	}
+}
+
+func TestGetCommunities(t *testing.T) {
+	assert := assert.New(t)
+	tests := []handlerTestCase{
+		{
+			name:                       "Success",
+			req:                        newRequest("GET", "/sixdegrees/communities", "application/json", nil),
+			driver:                     &dummyDriver{},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"label": "Test Person", "members": [{"id": "http://api.ft.com/things/12345", "prefLabel": "Test Person"}, {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person"}], "internalWeight": 3}]`,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedMaxNodes:           defaultCommunitiesMaxNodes,
+		},
+		{
+			name:                       "SuccessWithMaxNodesAndMinimumConnections",
+			req:                        newRequest("GET", "/sixdegrees/communities?maxNodes=50&minimumConnections=2", "application/json", nil),
+			driver:                     &dummyDriver{},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"label": "Test Person", "members": [{"id": "http://api.ft.com/things/12345", "prefLabel": "Test Person"}, {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person"}], "internalWeight": 3}]`,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: 2,
+			expectedMaxNodes:           50,
+		},
+		{
+			name:       "FailureWithTooManyNodes",
+			req:        newRequest("GET", "/sixdegrees/communities?maxNodes=501", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting maxNodes query param, err=maxNodes must be between 1 and 500"),
+		},
+		{
+			name:       "FailureWithInvalidMinConnections",
+			req:        newRequest("GET", "/sixdegrees/communities?minimumConnections=FAIL", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting minimumConnections query param, err=strconv.Atoi: parsing \\\"FAIL\\\": invalid syntax"),
+		},
+		{
+			name:                       "NotFound",
+			req:                        newRequest("GET", "/sixdegrees/communities", "application/json", nil),
+			driver:                     &dummyDriver{shouldReturnNotFound: true},
+			statusCode:                 http.StatusNotFound,
+			body:                       message("No result"),
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedMaxNodes:           defaultCommunitiesMaxNodes,
+		},
+		{
+			name:                       "ReadError",
+			req:                        newRequest("GET", "/sixdegrees/communities", "application/json", nil),
+			driver:                     &dummyDriver{shouldFail: true},
+			statusCode:                 http.StatusInternalServerError,
+			body:                       message("Error retrieving result from DB"),
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedMaxNodes:           defaultCommunitiesMaxNodes,
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+		assert.Equal(test.expectedMinimumConnections, test.driver.argMinimumConnections, fmt.Sprintf("%s: Wrong minimum connections", test.name))
+		assert.Equal(test.expectedMaxNodes, test.driver.argMaxNodes, fmt.Sprintf("%s: Wrong max nodes", test.name))
+	}
+}
+
+func TestGetCentralPeople(t *testing.T) {
+	assert := assert.New(t)
+	tests := []handlerTestCase{
+		{
+			name:                       "Success",
+			req:                        newRequest("GET", "/sixdegrees/centralPeople", "application/json", nil),
+			driver:                     &dummyDriver{},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"id": "http://api.ft.com/things/12345", "prefLabel": "Test Person", "score": 0.5}, {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person", "score": 0.5}]`,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedMaxNodes:           defaultCentralityMaxNodes,
+		},
+		{
+			name:                       "SuccessWithDegreeAndLimit",
+			req:                        newRequest("GET", "/sixdegrees/centralPeople?metric=degree&limit=1&maxNodes=50&minimumConnections=2", "application/json", nil),
+			driver:                     &dummyDriver{},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"id": "http://api.ft.com/things/12345", "prefLabel": "Test Person", "score": 3}]`,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: 2,
+			expectedMaxNodes:           50,
+		},
+		{
+			name:                       "SuccessWithBetweenness",
+			req:                        newRequest("GET", "/sixdegrees/centralPeople?metric=betweenness", "application/json", nil),
+			driver:                     &dummyDriver{},
+			statusCode:                 http.StatusOK,
+			body:                       `[{"id": "http://api.ft.com/things/12345", "prefLabel": "Test Person", "score": 0}, {"id": "http://api.ft.com/things/67890", "prefLabel": "Other Person", "score": 0}]`,
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedMaxNodes:           defaultCentralityMaxNodes,
+		},
+		{
+			name:       "FailureWithUnknownMetric",
+			req:        newRequest("GET", "/sixdegrees/centralPeople?metric=closeness", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting metric query param, must be one of pagerank, betweenness, degree: metric=closeness"),
+		},
+		{
+			name:       "FailureWithInvalidLimit",
+			req:        newRequest("GET", "/sixdegrees/centralPeople?limit=0", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting limit query param, err=limit must be at least 1"),
+		},
+		{
+			name:       "FailureWithTooManyNodes",
+			req:        newRequest("GET", "/sixdegrees/centralPeople?maxNodes=501", "application/json", nil),
+			driver:     &dummyDriver{},
+			statusCode: http.StatusBadRequest,
+			body:       message("Error converting maxNodes query param, err=maxNodes must be between 1 and 500"),
+		},
+		{
+			name:                       "NotFound",
+			req:                        newRequest("GET", "/sixdegrees/centralPeople", "application/json", nil),
+			driver:                     &dummyDriver{shouldReturnNotFound: true},
+			statusCode:                 http.StatusNotFound,
+			body:                       message("No result"),
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedMaxNodes:           defaultCentralityMaxNodes,
+		},
+		{
+			name:                       "ReadError",
+			req:                        newRequest("GET", "/sixdegrees/centralPeople", "application/json", nil),
+			driver:                     &dummyDriver{shouldFail: true},
+			statusCode:                 http.StatusInternalServerError,
+			body:                       message("Error retrieving result from DB"),
+			expectedFromDateEpoch:      getDefaultFromDate().Unix(),
+			expectedToDateEpoch:        getDefaultToDate().Unix(),
+			expectedMinimumConnections: defaultMinConnections,
+			expectedMaxNodes:           defaultCentralityMaxNodes,
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
+		handler.RegisterHandlers(router)
+		router.ServeHTTP(rec, test.req)
+		assert.True(test.statusCode == rec.Code, fmt.Sprintf("%s: Wrong response code, was %d, should be %d", test.name, rec.Code, test.statusCode))
+		assert.JSONEq(test.body, rec.Body.String(), fmt.Sprintf("%s: Wrong body", test.name))
+		assert.Equal(time.Unix(test.expectedFromDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argFromDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong from date", test.name))
+		assert.Equal(time.Unix(test.expectedToDateEpoch, 0).Format("2006-01-02"), time.Unix(test.driver.argToDateEpoch, 0).Format("2006-01-02"), fmt.Sprintf("%s: Wrong to date", test.name))
+		assert.Equal(test.expectedMinimumConnections, test.driver.argMinimumConnections, fmt.Sprintf("%s: Wrong minimum connections", test.name))
+		assert.Equal(test.expectedMaxNodes, test.driver.argMaxNodes, fmt.Sprintf("%s: Wrong max nodes", test.name))
+	}
+}
+
+func TestCheckConnectivity(t *testing.T) {
+	assert := assert.New(t)
+
+	timeTemplate := "2006-01-02T15:04:05.000Z"
+
+	testSuccessTimeStr := "2016-12-13T16:03:31.2382547+02:00"
+	testSuccessTime, _ := time.Parse(timeTemplate, testSuccessTimeStr)
+
+	testSuccessResponse := fthealth.HealthResult{
+		SchemaVersion: 1,
+		SystemCode:    "public-six-degrees-api",
+		Name:          "Public Six Degrees API",
+		Description:   "Six Degrees Backend provides mostMentionedPeople and connectedPeople endpoints for Six Degrees Frontend.",
+		Ok:            true,
+		Checks: []fthealth.CheckResult{
+			{
+				Name:             "Check connectivity to Neo4j - neoUrl is a parameter in hieradata for this service",
+				Ok:               true,
+				Severity:         3,
+				BusinessImpact:   "Unable to respond to Public Six Degrees",
+				PanicGuide:       "https://dewey.ft.com/public-six-degrees-api.html",
+				TechnicalSummary: "Cannot connect to Neo4j. If this check fails, check that Neo4j instance is up and running. You can find\n\t\t\t\tthe neoUrl as a parameter in hieradata for this service.",
+				CheckOutput:      "Connectivity to neo4j is ok",
+				LastUpdated:      testSuccessTime,
+			},
+		},
+	}
+
+	testErrorTimeStr := "2016-12-13T16:03:31.2387546+02:00"
+	testErrorTime, _ := time.Parse(timeTemplate, testErrorTimeStr)
+
 	testErrorResponse := fthealth.HealthResult{
 		SchemaVersion: 1,
 		SystemCode:    "public-six-degrees-api",
@@ -404,7 +1992,7 @@ func TestCheckConnectivity(t *testing.T) {
 	for _, test := range tests {
 		rec := httptest.NewRecorder()
 
-		httpHandler := Handler{test.driver, "max-age=360, public"}
+		httpHandler := NewHandler(test.driver, "max-age=360, public", testCursorSecret, QueryTimeouts{})
 		router := mux.NewRouter()
 
 		timedHC := fthealth.TimedHealthCheck{
@@ -444,6 +2032,42 @@ func TestCheckConnectivity(t *testing.T) {
 	}
 }
 
+func getConnectedPeople() []ConnectedPerson {
+	return []ConnectedPerson{
+		{
+			Person: Thing{ID: "http://api.ft.com/things/67890", PrefLabel: "Other Person"},
+			Count:  5,
+			Score:  5,
+			Content: []Content{
+				{
+					ID:            "c1",
+					APIURL:        "http://api.ft.com/content/c1",
+					Title:         "Some Title",
+					PublishedDate: "2016-12-13T19:18:01Z",
+					Type:          "Article",
+					Brands:        []Thing{{ID: "http://api.ft.com/things/b1", PrefLabel: "Some Brand"}},
+					Predicates:    []string{"about", "mentions"},
+				},
+			},
+		},
+	}
+}
+
+func getMentionedThings() []MentionedThing {
+	return []MentionedThing{
+		{
+			Thing: Thing{
+				ID:        "http://api.ft.com/things/12345",
+				APIURL:    "http://api.ft.com/people/12345",
+				PrefLabel: "Test Person",
+			},
+			Mentions:     7,
+			ContentCount: 6,
+			Score:        7,
+		},
+	}
+}
+
 func newRequest(method, url, contentType string, body []byte) *http.Request {
 	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
 	if err != nil {
@@ -458,56 +2082,232 @@ func message(errMsg string) string {
 }
 
 type dummyDriver struct {
+	sync.Mutex
 	contentUUID           string
 	shouldFail            bool
+	shouldTimeOut         bool
 	argLimit              int
 	argFromDateEpoch      int64
 	argToDateEpoch        int64
 	argMinimumConnections int
 	argContentLimit       int
+	argMaxHops            int
+	argDepth              int
 	shouldReturnNotFound  bool
+	mentionedThings       []MentionedThing
+	argConceptType        string
+	argPreviousFromEpoch  int64
+	argPreviousToEpoch    int64
+	argRankBy             string
+	argInterval           string
+	argFilter             MentionFilter
+	argMaxNodes           int
+	coMentionGraphCalls   int
+	argAfter              *PageKey
+	connectedPeople       []ConnectedPerson
+	argContentSort        string
+	argQuery              string
+	canonicalUUIDs        map[string]string
+	connectionContent     []Content
 }
 
-func (ds *dummyDriver) ConnectedPeople(uuid string, fromDateEpoch int64, toDateEpoch int64, limit int, minimumConnections int, contentLimit int) ([]ConnectedPerson, bool, error) {
-	ds.captureConnectedPeopleArgs(fromDateEpoch, toDateEpoch, limit, minimumConnections, contentLimit)
+func (ds *dummyDriver) ConnectedPeople(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]ConnectedPerson, error) {
+	// Batches ask for connected people concurrently
+	ds.Lock()
+	defer ds.Unlock()
+	ds.captureConnectedPeopleArgs(fromDateEpoch, toDateEpoch, page, minimumConnections, contentLimit)
+	ds.argContentSort = contentSort
+	ds.argFilter = filter
 
+	if ds.shouldTimeOut {
+		return nil, context.DeadlineExceeded
+	}
 	if ds.shouldFail {
-		return nil, false, errors.New("TEST failing to READ")
+		return nil, errors.New("TEST failing to READ")
+	}
+	if uuid == ds.contentUUID && ds.connectedPeople != nil {
+		return ds.connectedPeople, nil
 	}
 	if uuid == ds.contentUUID {
-		return []ConnectedPerson{}, true, nil
+		return []ConnectedPerson{}, nil
 	}
-	return nil, false, nil
+	return nil, ErrConceptNotFound
 }
 
-func (ds *dummyDriver) captureConnectedPeopleArgs(fromDateEpoch int64, toDateEpoch int64, limit int, minimumConnections int, contentLimit int) {
+func (ds *dummyDriver) captureConnectedPeopleArgs(fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int) {
 	ds.argFromDateEpoch = fromDateEpoch
 	ds.argToDateEpoch = toDateEpoch
-	ds.argLimit = limit
+	ds.argLimit = page.Limit
+	ds.argAfter = page.After
 	ds.argMinimumConnections = minimumConnections
 	ds.argContentLimit = contentLimit
 }
 
-func (ds *dummyDriver) MostMentioned(fromDateEpoch int64, toDateEpoch int64, limit int) ([]Thing, bool, error) {
-	ds.captureMostMentionedPeopleArgs(fromDateEpoch, toDateEpoch, limit)
+func (ds *dummyDriver) ConnectedConcepts(ctx context.Context, uuid string, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, minimumConnections int, contentLimit int, contentSort string, filter MentionFilter) ([]ConnectedConcept, error) {
+	ds.captureConnectedPeopleArgs(fromDateEpoch, toDateEpoch, page, minimumConnections, contentLimit)
+	ds.argContentSort = contentSort
+	ds.argFilter = filter
+	ds.argConceptType = conceptType
+
+	if ds.shouldFail {
+		return nil, errors.New("TEST failing to READ")
+	}
+	if uuid == ds.contentUUID {
+		return []ConnectedConcept{}, nil
+	}
+	return nil, ErrConceptNotFound
+}
+
+func (ds *dummyDriver) MostMentioned(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, filter MentionFilter) ([]MentionedThing, bool, error) {
+	ds.captureMostMentionedPeopleArgs(fromDateEpoch, toDateEpoch, page)
+	ds.argFilter = filter
+	ds.argConceptType = conceptType
+
+	if ds.shouldFail {
+		return nil, false, errors.New("TEST failing to READ")
+	}
+
+	if ds.shouldReturnNotFound {
+		return []MentionedThing{}, false, nil
+	}
+	if ds.mentionedThings != nil {
+		return ds.mentionedThings, true, nil
+	}
+	return []MentionedThing{}, true, nil
+}
+
+func (ds *dummyDriver) captureMostMentionedPeopleArgs(fromDateEpoch int64, toDateEpoch int64, page PageRequest) {
+	ds.argFromDateEpoch = fromDateEpoch
+	ds.argToDateEpoch = toDateEpoch
+	ds.argLimit = page.Limit
+	ds.argAfter = page.After
+}
+
+func (ds *dummyDriver) Trending(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, previousFromDateEpoch int64, previousToDateEpoch int64, limit int, rankBy string) ([]TrendingThing, bool, error) {
+	ds.captureMostMentionedPeopleArgs(fromDateEpoch, toDateEpoch, PageRequest{Limit: limit})
+	ds.argConceptType = conceptType
+	ds.argPreviousFromEpoch = previousFromDateEpoch
+	ds.argPreviousToEpoch = previousToDateEpoch
+	ds.argRankBy = rankBy
+
+	if ds.shouldFail {
+		return nil, false, errors.New("TEST failing to READ")
+	}
+	if ds.shouldReturnNotFound {
+		return []TrendingThing{}, false, nil
+	}
+	return []TrendingThing{}, true, nil
+}
+
+func (ds *dummyDriver) Mentions(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, interval string) ([]MentionsBucket, bool, error) {
+	ds.argFromDateEpoch = fromDateEpoch
+	ds.argToDateEpoch = toDateEpoch
+	ds.argInterval = interval
 
 	if ds.shouldFail {
 		return nil, false, errors.New("TEST failing to READ")
 	}
+	if uuid == ds.contentUUID {
+		return []MentionsBucket{{Date: "2017-01-09", Count: 2}, {Date: "2017-01-16", Count: 0}}, true, nil
+	}
+	return nil, false, nil
+}
+
+func (ds *dummyDriver) ShortestPath(ctx context.Context, fromUUID string, toUUID string, fromDateEpoch int64, toDateEpoch int64, maxHops int, contentLimit int) (ConnectionPath, bool, error) {
+	ds.argFromDateEpoch = fromDateEpoch
+	ds.argToDateEpoch = toDateEpoch
+	ds.argMaxHops = maxHops
+	ds.argContentLimit = contentLimit
+
+	if ds.shouldFail {
+		return ConnectionPath{}, false, errors.New("TEST failing to READ")
+	}
+	if fromUUID == ds.contentUUID {
+		return ConnectionPath{People: []Thing{}, Hops: []PathHop{}}, true, nil
+	}
+	return ConnectionPath{}, false, nil
+}
+
+func (ds *dummyDriver) Connection(ctx context.Context, uuid string, otherUUID string, fromDateEpoch int64, toDateEpoch int64, page PageRequest) (Connection, error) {
+	ds.argFromDateEpoch = fromDateEpoch
+	ds.argToDateEpoch = toDateEpoch
+	ds.argLimit = page.Limit
+	ds.argAfter = page.After
+
+	if ds.shouldFail {
+		return Connection{}, errors.New("TEST failing to READ")
+	}
+	if uuid != ds.contentUUID {
+		return Connection{}, ErrConceptNotFound
+	}
+	connection := Connection{People: []Thing{}, Content: []Content{}, Histogram: []MentionsBucket{}}
+	if ds.connectionContent != nil {
+		connection.Content = ds.connectionContent
+		connection.Count = len(ds.connectionContent)
+	}
+	return connection, nil
+}
+
+func (ds *dummyDriver) Network(ctx context.Context, uuid string, fromDateEpoch int64, toDateEpoch int64, depth int, limit int, minimumConnections int) (Network, bool, error) {
+	ds.argFromDateEpoch = fromDateEpoch
+	ds.argToDateEpoch = toDateEpoch
+	ds.argDepth = depth
+	ds.argLimit = limit
+	ds.argMinimumConnections = minimumConnections
+
+	if ds.shouldFail {
+		return Network{}, false, errors.New("TEST failing to READ")
+	}
+	if uuid == ds.contentUUID {
+		return Network{Nodes: []NetworkNode{}, Edges: []NetworkEdge{}}, true, nil
+	}
+	return Network{}, false, nil
+}
+
+func (ds *dummyDriver) CoMentionGraph(ctx context.Context, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) (CoMentionGraph, bool, error) {
+	ds.argFromDateEpoch = fromDateEpoch
+	ds.argToDateEpoch = toDateEpoch
+	ds.argMaxNodes = maxNodes
+	ds.argMinimumConnections = minimumConnections
+	ds.coMentionGraphCalls++
 
+	if ds.shouldFail {
+		return CoMentionGraph{}, false, errors.New("TEST failing to READ")
+	}
 	if ds.shouldReturnNotFound {
-		return []Thing{}, false, nil
+		return CoMentionGraph{}, false, nil
 	}
-	return []Thing{}, true, nil
+	return CoMentionGraph{
+		People: []Thing{{ID: "http://api.ft.com/things/12345", PrefLabel: "Test Person"}, {ID: "http://api.ft.com/things/67890", PrefLabel: "Other Person"}},
+		Edges:  []NetworkEdge{{Source: "http://api.ft.com/things/12345", Target: "http://api.ft.com/things/67890", Count: 3}},
+	}, true, nil
 }
 
-func (ds *dummyDriver) captureMostMentionedPeopleArgs(fromDateEpoch int64, toDateEpoch int64, limit int) {
+func (ds *dummyDriver) SearchPeople(ctx context.Context, query string, fromDateEpoch int64, toDateEpoch int64, limit int) ([]MatchedThing, error) {
+	ds.argQuery = query
 	ds.argFromDateEpoch = fromDateEpoch
 	ds.argToDateEpoch = toDateEpoch
 	ds.argLimit = limit
+
+	if ds.shouldFail {
+		return nil, errors.New("TEST failing to READ")
+	}
+	if strings.HasPrefix(strings.ToLower(query), "test") {
+		return []MatchedThing{{Thing: Thing{ID: "http://api.ft.com/things/12345", PrefLabel: "Test Person"}, Match: "Test Person", Mentions: 4}}, nil
+	}
+	return []MatchedThing{}, nil
+}
+
+func (ds *dummyDriver) CanonicalUUID(ctx context.Context, identifier string) (string, bool, error) {
+	canonicalUUID, found := ds.canonicalUUIDs[identifier]
+	return canonicalUUID, found, nil
+}
+
+func (ds *dummyDriver) CentralPeople(ctx context.Context, metric string, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) ([]CentralThing, bool, error) {
+	return centralPeople(ctx, ds, metric, fromDateEpoch, toDateEpoch, maxNodes, minimumConnections)
 }
 
-func (ds *dummyDriver) CheckConnectivity() error {
+func (ds *dummyDriver) CheckConnectivity(ctx context.Context) error {
 	if ds.shouldFail {
 		return errors.New("TEST failing check connectivity")
 	}
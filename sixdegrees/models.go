@@ -6,18 +6,117 @@ type Thing struct {
 	PrefLabel string `json:"prefLabel,omitempty"`
 }
 
+type MentionedThing struct {
+	Thing
+	Mentions     int     `json:"mentions"`
+	ContentCount int     `json:"contentCount"`
+	Score        float64 `json:"score"`
+}
+
+type TrendingThing struct {
+	Thing
+	Mentions         int     `json:"mentions"`
+	PreviousMentions int     `json:"previousMentions"`
+	Delta            int     `json:"delta"`
+	Growth           float64 `json:"growth"`
+}
+
+// MatchedThing is a search result, with the label or alias it matched by
+type MatchedThing struct {
+	Thing
+	Match    string `json:"match"`
+	Mentions int    `json:"mentions"`
+}
+
+type CentralThing struct {
+	Thing
+	Score float64 `json:"score"`
+}
+
+type MentionsBucket struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
 type Content struct {
-	ID     string `json:"id"`
-	APIURL string `json:"apiUrl,omitempty"`
-	Title  string `json:"title"`
+	ID            string   `json:"id"`
+	APIURL        string   `json:"apiUrl,omitempty"`
+	Title         string   `json:"title"`
+	PublishedDate string   `json:"publishedDate,omitempty"`
+	Type          string   `json:"type,omitempty"`
+	Brands        []Thing  `json:"brands,omitempty"`
+	Predicates    []string `json:"predicates,omitempty"`
 }
 
 type ConnectedPerson struct {
 	Person  Thing     `json:"person"`
 	Count   int       `json:"count"`
+	Score   float64   `json:"score"`
+	Content []Content `json:"content"`
+}
+
+// unscoredConnectedPerson is a connected person as v1 answers it without a weighting, from before connections were scored
+type unscoredConnectedPerson struct {
+	Person  Thing     `json:"person"`
+	Count   int       `json:"count"`
+	Content []Content `json:"content"`
+}
+
+type ConnectedConcept struct {
+	Concept Thing     `json:"concept"`
+	Count   int       `json:"count"`
+	Score   float64   `json:"score"`
 	Content []Content `json:"content"`
 }
 
+type PathHop struct {
+	From    Thing     `json:"from"`
+	To      Thing     `json:"to"`
+	Content []Content `json:"content"`
+}
+
+type ConnectionPath struct {
+	People []Thing   `json:"people"`
+	Hops   []PathHop `json:"hops"`
+}
+
+// Connection is the content two people are co-mentioned in, a page of it, with the count and daily histogram of all of it
+type Connection struct {
+	People    []Thing          `json:"people"`
+	Count     int              `json:"count"`
+	Content   []Content        `json:"content"`
+	Histogram []MentionsBucket `json:"histogram"`
+}
+
+type NetworkNode struct {
+	Person   Thing `json:"person"`
+	Distance int   `json:"distance"`
+	Mentions int   `json:"mentions"`
+}
+
+type NetworkEdge struct {
+	Source  string   `json:"source"`
+	Target  string   `json:"target"`
+	Count   int      `json:"count"`
+	Content []string `json:"content"`
+}
+
+type Network struct {
+	Nodes []NetworkNode `json:"nodes"`
+	Edges []NetworkEdge `json:"edges"`
+}
+
+type CoMentionGraph struct {
+	People []Thing       `json:"people"`
+	Edges  []NetworkEdge `json:"edges"`
+}
+
+type Community struct {
+	Label          string  `json:"label"`
+	Members        []Thing `json:"members"`
+	InternalWeight int     `json:"internalWeight"`
+}
+
 type ErrorMessage struct {
 	Message string `json:"message"`
 }
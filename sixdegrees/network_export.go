@@ -0,0 +1,303 @@
+package sixdegrees
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const defaultNetworkFormat = "json"
+
+type networkFormat struct {
+	mediaType string
+	encode    func(io.Writer, Network) error
+}
+
+// The formats a network can be exported in, so it can be opened straight into graph tools such as Gephi or Cytoscape
+var networkFormats = map[string]networkFormat{
+	"json":      {mediaType: "application/json; charset=UTF-8", encode: encodeNetworkJSON},
+	"graphml":   {mediaType: "application/graphml+xml; charset=UTF-8", encode: encodeGraphML},
+	"gexf":      {mediaType: "application/gexf+xml; charset=UTF-8", encode: encodeGEXF},
+	"dot":       {mediaType: "text/vnd.graphviz; charset=UTF-8", encode: encodeDOT},
+	"cytoscape": {mediaType: "application/json; charset=UTF-8", encode: encodeCytoscape},
+}
+
+// In order of preference when negotiating, so plain JSON wins over Cytoscape's
+var networkFormatNames = []string{"json", "graphml", "gexf", "dot", "cytoscape"}
+
+// getNetworkFormat prefers the format query param, then the first format the Accept header asks for by its media type
+func getNetworkFormat(formatParam string, acceptHeader string) (string, error) {
+	if formatParam != "" {
+		if _, found := networkFormats[formatParam]; !found {
+			return "", fmt.Errorf("must be one of %s", strings.Join(networkFormatNames, ", "))
+		}
+		return formatParam, nil
+	}
+
+	for _, accepted := range strings.Split(acceptHeader, ",") {
+		mediaType := strings.TrimSpace(strings.Split(accepted, ";")[0])
+		for _, format := range networkFormatNames {
+			if strings.HasPrefix(networkFormats[format].mediaType, mediaType+";") {
+				return format, nil
+			}
+		}
+	}
+	return defaultNetworkFormat, nil
+}
+
+func encodeNetworkJSON(w io.Writer, network Network) error {
+	return json.NewEncoder(w).Encode(network)
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func encodeGraphML(w io.Writer, network Network) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "apiUrl", For: "node", Name: "apiUrl", Type: "string"},
+			{ID: "distance", For: "node", Name: "distance", Type: "int"},
+			{ID: "mentions", For: "node", Name: "mentions", Type: "int"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+			{ID: "content", For: "edge", Name: "content", Type: "string"},
+		},
+		Graph: graphMLGraph{ID: "network", EdgeDefault: "undirected"},
+	}
+	for _, node := range network.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: node.Person.ID,
+			Data: []graphMLData{
+				{Key: "label", Value: node.Person.PrefLabel},
+				{Key: "apiUrl", Value: node.Person.APIURL},
+				{Key: "distance", Value: strconv.Itoa(node.Distance)},
+				{Key: "mentions", Value: strconv.Itoa(node.Mentions)},
+			},
+		})
+	}
+	for i, edge := range network.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: edge.Source,
+			Target: edge.Target,
+			Data: []graphMLData{
+				{Key: "weight", Value: strconv.Itoa(edge.Count)},
+				{Key: "content", Value: strings.Join(edge.Content, " ")},
+			},
+		})
+	}
+	return encodeXML(w, doc)
+}
+
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Weight    int            `xml:"weight,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+func encodeGEXF(w io.Writer, network Network) error {
+	doc := gexf{
+		XMLNS:   "http://www.gexf.net/1.2draft",
+		Version: "1.2",
+		Graph: gexfGraph{
+			DefaultEdgeType: "undirected",
+			Attributes: []gexfAttributes{
+				{Class: "node", Attributes: []gexfAttribute{
+					{ID: "apiUrl", Title: "apiUrl", Type: "string"},
+					{ID: "distance", Title: "distance", Type: "integer"},
+					{ID: "mentions", Title: "mentions", Type: "integer"},
+				}},
+				{Class: "edge", Attributes: []gexfAttribute{
+					{ID: "content", Title: "content", Type: "string"},
+				}},
+			},
+			Nodes: []gexfNode{},
+			Edges: []gexfEdge{},
+		},
+	}
+	for _, node := range network.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
+			ID:    node.Person.ID,
+			Label: node.Person.PrefLabel,
+			AttValues: []gexfAttValue{
+				{For: "apiUrl", Value: node.Person.APIURL},
+				{For: "distance", Value: strconv.Itoa(node.Distance)},
+				{For: "mentions", Value: strconv.Itoa(node.Mentions)},
+			},
+		})
+	}
+	for i, edge := range network.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:        strconv.Itoa(i),
+			Source:    edge.Source,
+			Target:    edge.Target,
+			Weight:    edge.Count,
+			AttValues: []gexfAttValue{{For: "content", Value: strings.Join(edge.Content, " ")}},
+		})
+	}
+	return encodeXML(w, doc)
+}
+
+func encodeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func encodeDOT(w io.Writer, network Network) error {
+	var dot strings.Builder
+	dot.WriteString("graph network {\n")
+	for _, node := range network.Nodes {
+		fmt.Fprintf(&dot, "  %s [label=%s, apiUrl=%s, distance=%d, mentions=%d];\n",
+			dotID(node.Person.ID), dotID(node.Person.PrefLabel), dotID(node.Person.APIURL), node.Distance, node.Mentions)
+	}
+	for _, edge := range network.Edges {
+		fmt.Fprintf(&dot, "  %s -- %s [weight=%d, content=%s];\n",
+			dotID(edge.Source), dotID(edge.Target), edge.Count, dotID(strings.Join(edge.Content, " ")))
+	}
+	dot.WriteString("}\n")
+
+	_, err := io.WriteString(w, dot.String())
+	return err
+}
+
+// Every DOT ID is quoted, as people's names and URLs are rarely valid bare IDs
+func dotID(id string) string {
+	return `"` + strings.Replace(strings.Replace(id, `\`, `\\`, -1), `"`, `\"`, -1) + `"`
+}
+
+type cytoscapeElements struct {
+	Elements struct {
+		Nodes []cytoscapeElement `json:"nodes"`
+		Edges []cytoscapeElement `json:"edges"`
+	} `json:"elements"`
+}
+
+type cytoscapeElement struct {
+	Data interface{} `json:"data"`
+}
+
+type cytoscapeNode struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	APIURL   string `json:"apiUrl,omitempty"`
+	Distance int    `json:"distance"`
+	Mentions int    `json:"mentions"`
+}
+
+type cytoscapeEdge struct {
+	ID      string   `json:"id"`
+	Source  string   `json:"source"`
+	Target  string   `json:"target"`
+	Weight  int      `json:"weight"`
+	Content []string `json:"content"`
+}
+
+func encodeCytoscape(w io.Writer, network Network) error {
+	doc := cytoscapeElements{}
+	doc.Elements.Nodes = []cytoscapeElement{}
+	doc.Elements.Edges = []cytoscapeElement{}
+	for _, node := range network.Nodes {
+		doc.Elements.Nodes = append(doc.Elements.Nodes, cytoscapeElement{Data: cytoscapeNode{
+			ID:       node.Person.ID,
+			Label:    node.Person.PrefLabel,
+			APIURL:   node.Person.APIURL,
+			Distance: node.Distance,
+			Mentions: node.Mentions,
+		}})
+	}
+	for i, edge := range network.Edges {
+		doc.Elements.Edges = append(doc.Elements.Edges, cytoscapeElement{Data: cytoscapeEdge{
+			ID:      fmt.Sprintf("e%d", i),
+			Source:  edge.Source,
+			Target:  edge.Target,
+			Weight:  edge.Count,
+			Content: edge.Content,
+		}})
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
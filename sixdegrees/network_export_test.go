@@ -0,0 +1,156 @@
+package sixdegrees
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, encodeGraphML(&buf, getExportedNetwork()))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="label" for="node" attr.name="label" attr.type="string"></key>
+  <key id="apiUrl" for="node" attr.name="apiUrl" attr.type="string"></key>
+  <key id="distance" for="node" attr.name="distance" attr.type="int"></key>
+  <key id="mentions" for="node" attr.name="mentions" attr.type="int"></key>
+  <key id="weight" for="edge" attr.name="weight" attr.type="int"></key>
+  <key id="content" for="edge" attr.name="content" attr.type="string"></key>
+  <graph id="network" edgedefault="undirected">
+    <node id="http://api.ft.com/things/a">
+      <data key="label">Ann &amp; Co</data>
+      <data key="apiUrl">http://api.ft.com/people/a</data>
+      <data key="distance">0</data>
+      <data key="mentions">7</data>
+    </node>
+    <node id="http://api.ft.com/things/b">
+      <data key="label">Bob &#34;The Builder&#34;</data>
+      <data key="apiUrl">http://api.ft.com/people/b</data>
+      <data key="distance">1</data>
+      <data key="mentions">3</data>
+    </node>
+    <edge id="e0" source="http://api.ft.com/things/a" target="http://api.ft.com/things/b">
+      <data key="weight">2</data>
+      <data key="content">c1 c2</data>
+    </edge>
+  </graph>
+</graphml>
+`
+	assert.Equal(t, expected, buf.String())
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &graphML{}), "GraphML should be well formed")
+}
+
+func TestEncodeGEXF(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, encodeGEXF(&buf, getExportedNetwork()))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">
+  <graph defaultedgetype="undirected">
+    <attributes class="node">
+      <attribute id="apiUrl" title="apiUrl" type="string"></attribute>
+      <attribute id="distance" title="distance" type="integer"></attribute>
+      <attribute id="mentions" title="mentions" type="integer"></attribute>
+    </attributes>
+    <attributes class="edge">
+      <attribute id="content" title="content" type="string"></attribute>
+    </attributes>
+    <nodes>
+      <node id="http://api.ft.com/things/a" label="Ann &amp; Co">
+        <attvalues>
+          <attvalue for="apiUrl" value="http://api.ft.com/people/a"></attvalue>
+          <attvalue for="distance" value="0"></attvalue>
+          <attvalue for="mentions" value="7"></attvalue>
+        </attvalues>
+      </node>
+      <node id="http://api.ft.com/things/b" label="Bob &#34;The Builder&#34;">
+        <attvalues>
+          <attvalue for="apiUrl" value="http://api.ft.com/people/b"></attvalue>
+          <attvalue for="distance" value="1"></attvalue>
+          <attvalue for="mentions" value="3"></attvalue>
+        </attvalues>
+      </node>
+    </nodes>
+    <edges>
+      <edge id="0" source="http://api.ft.com/things/a" target="http://api.ft.com/things/b" weight="2">
+        <attvalues>
+          <attvalue for="content" value="c1 c2"></attvalue>
+        </attvalues>
+      </edge>
+    </edges>
+  </graph>
+</gexf>
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestEncodeDOT(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, encodeDOT(&buf, getExportedNetwork()))
+
+	expected := `graph network {
+  "http://api.ft.com/things/a" [label="Ann & Co", apiUrl="http://api.ft.com/people/a", distance=0, mentions=7];
+  "http://api.ft.com/things/b" [label="Bob \"The Builder\"", apiUrl="http://api.ft.com/people/b", distance=1, mentions=3];
+  "http://api.ft.com/things/a" -- "http://api.ft.com/things/b" [weight=2, content="c1 c2"];
+}
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestEncodeCytoscape(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, encodeCytoscape(&buf, getExportedNetwork()))
+
+	expected := `{"elements": {
+		"nodes": [
+			{"data": {"id": "http://api.ft.com/things/a", "label": "Ann & Co", "apiUrl": "http://api.ft.com/people/a", "distance": 0, "mentions": 7}},
+			{"data": {"id": "http://api.ft.com/things/b", "label": "Bob \"The Builder\"", "apiUrl": "http://api.ft.com/people/b", "distance": 1, "mentions": 3}}
+		],
+		"edges": [
+			{"data": {"id": "e0", "source": "http://api.ft.com/things/a", "target": "http://api.ft.com/things/b", "weight": 2, "content": ["c1", "c2"]}}
+		]
+	}}`
+	assert.JSONEq(t, expected, buf.String())
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &cytoscapeElements{}))
+}
+
+func TestGetNetworkFormat(t *testing.T) {
+	tests := []struct {
+		formatParam    string
+		acceptHeader   string
+		expectedFormat string
+		expectedError  bool
+	}{
+		{"", "", "json", false},
+		{"", "*/*", "json", false},
+		{"", "application/json", "json", false},
+		{"", "application/gexf+xml", "gexf", false},
+		{"", "text/plain, text/vnd.graphviz", "dot", false},
+		{"cytoscape", "application/json", "cytoscape", false},
+		{"graphml", "", "graphml", false},
+		{"pajek", "", "", true},
+	}
+
+	for _, test := range tests {
+		format, err := getNetworkFormat(test.formatParam, test.acceptHeader)
+		assert.Equal(t, test.expectedFormat, format, "format=%s, Accept=%s: Wrong format", test.formatParam, test.acceptHeader)
+		assert.Equal(t, test.expectedError, err != nil, "format=%s, Accept=%s: Wrong error %v", test.formatParam, test.acceptHeader, err)
+	}
+}
+
+func getExportedNetwork() Network {
+	return Network{
+		Nodes: []NetworkNode{
+			{Person: Thing{ID: "http://api.ft.com/things/a", APIURL: "http://api.ft.com/people/a", PrefLabel: "Ann & Co"}, Distance: 0, Mentions: 7},
+			{Person: Thing{ID: "http://api.ft.com/things/b", APIURL: "http://api.ft.com/people/b", PrefLabel: `Bob "The Builder"`}, Distance: 1, Mentions: 3},
+		},
+		Edges: []NetworkEdge{
+			{Source: "http://api.ft.com/things/a", Target: "http://api.ft.com/things/b", Count: 2, Content: []string{"c1", "c2"}},
+		},
+	}
+}
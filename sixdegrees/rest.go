@@ -0,0 +1,93 @@
+package sixdegrees
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	logger "github.com/Financial-Times/go-logger"
+	"github.com/Financial-Times/neo-utils-go/neoutils"
+	"github.com/jmcvetta/neoism"
+)
+
+// Queries of a batch whose context is done are killed again at this interval, until the batch returns, as a batch
+// runs its queries one after the other and the next one may start after the previous is killed
+const restKillInterval = time.Second
+
+// NewRESTConnection lets the REST connection of neoutils abort its queries, whose HTTP requests cannot be cancelled
+func NewRESTConnection(conn neoutils.NeoConnection) neoutils.NeoConnection {
+	return &RESTConnection{NeoConnection: conn}
+}
+
+// RESTConnection marks every query of a batch with a comment of its own, for Neo4j to kill the queries marked so once
+// the context of their batch is done
+type RESTConnection struct {
+	neoutils.NeoConnection
+}
+
+func (rc *RESTConnection) CypherBatch(queries []*neoism.CypherQuery) error {
+	return rc.CypherBatchContext(context.Background(), queries)
+}
+
+// CypherBatchContext answers with the error of the context once it is done, killing the queries of the batch until it
+// returns
+func (rc *RESTConnection) CypherBatchContext(ctx context.Context, queries []*neoism.CypherQuery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	marker, err := newQueryMarker()
+	if err != nil {
+		return err
+	}
+	marked := []*neoism.CypherQuery{}
+	for _, query := range queries {
+		markedQuery := *query
+		markedQuery.Statement = "// " + marker + "\n" + query.Statement
+		marked = append(marked, &markedQuery)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- rc.NeoConnection.CypherBatch(marked)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		go rc.killUntilDone(marker, done)
+		return ctx.Err()
+	}
+}
+
+func (rc *RESTConnection) killUntilDone(marker string, done <-chan error) {
+	for {
+		err := rc.NeoConnection.CypherBatch([]*neoism.CypherQuery{{
+			Statement: `
+				CALL dbms.listQueries() YIELD queryId, query
+				WHERE query STARTS WITH $marker
+				CALL dbms.killQuery(queryId) YIELD message
+				RETURN message
+			`,
+			Parameters: neoism.Props{"marker": "// " + marker},
+		}})
+		if err != nil {
+			logger.WithError(err).WithField("marker", marker).Warn("could not kill queries")
+		}
+
+		select {
+		case <-done:
+			return
+		case <-time.After(restKillInterval):
+		}
+	}
+}
+
+func newQueryMarker() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return "public-six-degrees query " + hex.EncodeToString(id), nil
+}
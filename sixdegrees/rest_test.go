@@ -0,0 +1,77 @@
+package sixdegrees
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/neo-utils-go/neoutils"
+	"github.com/jmcvetta/neoism"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTCypherBatchKillsCancelledQueries(t *testing.T) {
+	db := &killableRESTConn{started: make(chan struct{}), killed: make(chan struct{}), stopped: make(chan struct{})}
+	conn := NewRESTConnection(db).(*RESTConnection)
+	query := &neoism.CypherQuery{Statement: "MATCH (p:Person) RETURN p.prefUUID as uuid"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	answered := make(chan error, 1)
+	go func() {
+		answered <- conn.CypherBatchContext(ctx, []*neoism.CypherQuery{query})
+	}()
+	<-db.started
+	cancel()
+
+	assert.Equal(t, context.Canceled, <-answered)
+	select {
+	case <-db.stopped:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "The cancelled query should have been killed")
+	}
+	assert.True(t, strings.HasSuffix(db.running, "\nMATCH (p:Person) RETURN p.prefUUID as uuid"), "Queries should be run as given after their marker")
+	assert.Equal(t, "MATCH (p:Person) RETURN p.prefUUID as uuid", query.Statement, "Queries given should be left as they are")
+}
+
+func TestRESTCypherBatchFailure(t *testing.T) {
+	db := &killableRESTConn{started: make(chan struct{}, 1), killed: make(chan struct{}), stopped: make(chan struct{})}
+	close(db.killed)
+	conn := NewRESTConnection(db).(*RESTConnection)
+
+	err := conn.CypherBatchContext(context.Background(), []*neoism.CypherQuery{{Statement: "MATCH (p:Person) RETURN p.prefUUID as uuid"}})
+	assert.EqualError(t, err, "TEST query killed")
+}
+
+// killableRESTConn runs a query until a query killing the queries with its marker comes
+type killableRESTConn struct {
+	neoutils.NeoConnection
+	started chan struct{}
+	killed  chan struct{}
+	stopped chan struct{}
+	lock    sync.Mutex
+	running string
+	kill    sync.Once
+}
+
+func (c *killableRESTConn) CypherBatch(queries []*neoism.CypherQuery) error {
+	if strings.Contains(queries[0].Statement, "dbms.killQuery") {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		if strings.HasPrefix(c.running, queries[0].Parameters["marker"].(string)) {
+			c.kill.Do(func() { close(c.killed) })
+		}
+		return nil
+	}
+
+	c.lock.Lock()
+	c.running = queries[0].Statement
+	c.lock.Unlock()
+	c.started <- struct{}{}
+	<-c.killed
+	close(c.stopped)
+	return errors.New("TEST query killed")
+}
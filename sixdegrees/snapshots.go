@@ -0,0 +1,197 @@
+package sixdegrees
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Financial-Times/neo-utils-go/neoutils"
+	"github.com/jmcvetta/neoism"
+)
+
+// Snapshots are looked up by day, which every summary node is indexed on
+var snapshotIndexes = map[string]string{
+	"DailyMentions":   "day",
+	"DailyCoMentions": "day",
+	"MentionSnapshot": "day",
+}
+
+// SnapshotDays are the days before today to build snapshots of, newest first, as UTC midnights
+func SnapshotDays(now time.Time, count int) []time.Time {
+	today := now.UTC().Truncate(24 * time.Hour)
+	days := []time.Time{}
+	for i := 1; i <= count; i++ {
+		days = append(days, today.AddDate(0, 0, -i))
+	}
+	return days
+}
+
+// BuildSnapshots summarises the mentions of every concept, and the co-mentions of every pair of people, of each of the
+// given days, replacing any previous summary of the day. A day is marked as built only once all of its summaries are,
+// as drivers sum the days marked so
+func BuildSnapshots(conn neoutils.NeoConnection, days []time.Time) error {
+	if err := conn.EnsureIndexes(snapshotIndexes); err != nil {
+		return err
+	}
+	for _, day := range days {
+		if err := conn.CypherBatch(snapshotQueries(day.UTC().Truncate(24*time.Hour).Unix(), time.Now().Unix())); err != nil {
+			return fmt.Errorf("could not build snapshot of %s: %v", day.Format("2006-01-02"), err)
+		}
+	}
+	return nil
+}
+
+// Summaries only count the mentions predicate, which is the default of every endpoint
+func snapshotQueries(day int64, builtAt int64) []*neoism.CypherQuery {
+	params := neoism.Props{
+		"day":     day,
+		"nextDay": day + secondsPerDay,
+		"builtAt": builtAt,
+	}
+
+	queries := []*neoism.CypherQuery{
+		{Statement: `MATCH (s:MentionSnapshot {day: $day}) DELETE s`, Parameters: params},
+		{Statement: `MATCH (s:DailyMentions {day: $day}) DELETE s`, Parameters: params},
+		{Statement: `MATCH (s:DailyCoMentions {day: $day}) DELETE s`, Parameters: params},
+	}
+	for _, conceptType := range conceptTypes {
+		queries = append(queries, &neoism.CypherQuery{
+			Statement: fmt.Sprintf(`
+				MATCH (c:Content)
+				WHERE
+					c.publishedDateEpoch >= $day
+					AND c.publishedDateEpoch < $nextDay
+				MATCH (c)-[a:MENTIONS]->(:%[1]s)-[:EQUIVALENT_TO]->(p:%[1]s)
+				WITH
+					p.prefUUID as uuid,
+					COUNT(a) as mentions,
+					COUNT(DISTINCT c) as contentCount,
+					SUM(coalesce(a.relevanceScore, 1.0)) as relevance,
+					SUM(coalesce(a.confidenceScore, 1.0)) as confidence
+				CREATE (:DailyMentions {
+					day: $day,
+					conceptType: $conceptType,
+					uuid: uuid,
+					mentions: mentions,
+					contentCount: contentCount,
+					relevance: relevance,
+					confidence: confidence
+				})`, conceptType),
+			Parameters: neoism.Props{
+				"day":         day,
+				"nextDay":     day + secondsPerDay,
+				"conceptType": conceptType,
+			},
+		})
+	}
+	queries = append(queries,
+		&neoism.CypherQuery{
+			Statement: `
+				MATCH (c:Content)
+				WHERE
+					c.publishedDateEpoch >= $day
+					AND c.publishedDateEpoch < $nextDay
+				MATCH (p:Person)<-[:EQUIVALENT_TO]-(:Person)<-[:MENTIONS]-(c)-[:MENTIONS]->(:Person)-[:EQUIVALENT_TO]->(p2:Person)
+				WHERE p.prefUUID < p2.prefUUID
+				WITH
+					p.prefUUID as uuid,
+					p2.prefUUID as otherUUID,
+					collect(distinct(c.uuid)) as content
+				CREATE (:DailyCoMentions {
+					day: $day,
+					uuid: uuid,
+					otherUUID: otherUUID,
+					count: size(content),
+					content: content
+				})`,
+			Parameters: params,
+		},
+		&neoism.CypherQuery{
+			Statement:  `CREATE (:MentionSnapshot {day: $day, builtAt: $builtAt})`,
+			Parameters: params,
+		},
+	)
+	return queries
+}
+
+// splitSnapshotWindow splits a window into the built days wholly within it, after its start, and the ranges around them
+// to query live. Ranges are exclusive, like windows, so a day starting at midnight is the range from a second before it
+func splitSnapshotWindow(fromDateEpoch int64, toDateEpoch int64, built []int64) ([]int64, [][]int64) {
+	days := []int64{}
+	live := [][]int64{}
+	after := fromDateEpoch
+	for _, day := range built {
+		if day <= after || day+secondsPerDay > toDateEpoch {
+			continue
+		}
+		if day-after > 1 {
+			live = append(live, []int64{after, day})
+		}
+		days = append(days, day)
+		after = day + secondsPerDay - 1
+	}
+	if toDateEpoch-after > 1 {
+		live = append(live, []int64{after, toDateEpoch})
+	}
+	return days, live
+}
+
+// SnapshotDriver answers the most mentioned concepts and the co-mention graph of long windows by summing the built days
+// of their snapshots, querying live only the rest of the window, like today. Other queries, and filters other than the
+// mentions predicate alone, are answered live by the given driver
+type SnapshotDriver struct {
+	Driver
+	snapshots CypherDriver
+	minWindow time.Duration
+}
+
+func NewSnapshotDriver(driver Driver, conn neoutils.NeoConnection, minWindow time.Duration) *SnapshotDriver {
+	return &SnapshotDriver{
+		Driver:    driver,
+		snapshots: CypherDriver{conn},
+		minWindow: minWindow,
+	}
+}
+
+func (sd *SnapshotDriver) MostMentioned(ctx context.Context, conceptType string, fromDateEpoch int64, toDateEpoch int64, page PageRequest, filter MentionFilter) ([]MentionedThing, bool, error) {
+	if !sd.isLong(fromDateEpoch, toDateEpoch) || !isSnapshotFilter(filter) {
+		return sd.Driver.MostMentioned(ctx, conceptType, fromDateEpoch, toDateEpoch, page, filter)
+	}
+	days, live, err := sd.snapshots.snapshotWindow(ctx, fromDateEpoch, toDateEpoch)
+	if err != nil {
+		return []MentionedThing{}, false, err
+	}
+	if len(days) == 0 {
+		return sd.Driver.MostMentioned(ctx, conceptType, fromDateEpoch, toDateEpoch, page, filter)
+	}
+	return sd.snapshots.mostMentionedFromSnapshots(ctx, conceptType, days, live, page, filter)
+}
+
+func (sd *SnapshotDriver) CoMentionGraph(ctx context.Context, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) (CoMentionGraph, bool, error) {
+	if !sd.isLong(fromDateEpoch, toDateEpoch) {
+		return sd.Driver.CoMentionGraph(ctx, fromDateEpoch, toDateEpoch, maxNodes, minimumConnections)
+	}
+	days, live, err := sd.snapshots.snapshotWindow(ctx, fromDateEpoch, toDateEpoch)
+	if err != nil {
+		return CoMentionGraph{}, false, err
+	}
+	if len(days) == 0 {
+		return sd.Driver.CoMentionGraph(ctx, fromDateEpoch, toDateEpoch, maxNodes, minimumConnections)
+	}
+	return sd.snapshots.coMentionGraphFromSnapshots(ctx, days, live, maxNodes, minimumConnections)
+}
+
+// CentralPeople ranks the co-mention graph of the snapshots for long windows as well
+func (sd *SnapshotDriver) CentralPeople(ctx context.Context, metric string, fromDateEpoch int64, toDateEpoch int64, maxNodes int, minimumConnections int) ([]CentralThing, bool, error) {
+	return centralPeople(ctx, sd, metric, fromDateEpoch, toDateEpoch, maxNodes, minimumConnections)
+}
+
+func (sd *SnapshotDriver) isLong(fromDateEpoch int64, toDateEpoch int64) bool {
+	return sd.minWindow > 0 && toDateEpoch-fromDateEpoch >= int64(sd.minWindow/time.Second)
+}
+
+// Summaries hold every mention of the mentions predicate, whatever its scores, of content of any brand and type
+func isSnapshotFilter(filter MentionFilter) bool {
+	return len(filter.Predicates) == 1 && filter.Predicates[0] == "mentions" &&
+		filter.MinConfidence <= 0 && len(filter.Brands) == 0 && len(filter.ContentTypes) == 0
+}
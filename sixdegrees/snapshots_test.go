@@ -0,0 +1,116 @@
+package sixdegrees
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotDays(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2017, 5, 8, 10, 30, 0, 0, time.FixedZone("BST", 60*60))
+
+	assert.Equal([]time.Time{
+		time.Date(2017, 5, 7, 0, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 6, 0, 0, 0, 0, time.UTC),
+		time.Date(2017, 5, 5, 0, 0, 0, 0, time.UTC),
+	}, SnapshotDays(now, 3))
+	assert.Empty(SnapshotDays(now, 0))
+}
+
+func TestSplitSnapshotWindow(t *testing.T) {
+	assert := assert.New(t)
+	day := func(d int) int64 {
+		return time.Date(2017, 5, d, 0, 0, 0, 0, time.UTC).Unix()
+	}
+	tests := []struct {
+		name          string
+		fromDateEpoch int64
+		toDateEpoch   int64
+		built         []int64
+		days          []int64
+		live          [][]int64
+	}{
+		{
+			name:          "NoDaysBuilt",
+			fromDateEpoch: day(1),
+			toDateEpoch:   day(4),
+			built:         []int64{},
+			days:          []int64{},
+			live:          [][]int64{{day(1), day(4)}},
+		},
+		{
+			name:          "WholeDays",
+			fromDateEpoch: day(1) - 1,
+			toDateEpoch:   day(4),
+			built:         []int64{day(1), day(2), day(3)},
+			days:          []int64{day(1), day(2), day(3)},
+			live:          [][]int64{},
+		},
+		{
+			name:          "DayNotBuilt",
+			fromDateEpoch: day(1) - 1,
+			toDateEpoch:   day(4),
+			built:         []int64{day(1), day(3)},
+			days:          []int64{day(1), day(3)},
+			live:          [][]int64{{day(2) - 1, day(3)}},
+		},
+		{
+			name:          "WindowEndingNow",
+			fromDateEpoch: day(1) + 3600,
+			toDateEpoch:   day(4) + 3600,
+			built:         []int64{day(2), day(3)},
+			days:          []int64{day(2), day(3)},
+			live:          [][]int64{{day(1) + 3600, day(2)}, {day(4) - 1, day(4) + 3600}},
+		},
+		{
+			name:          "DaysOutsideWindow",
+			fromDateEpoch: day(2),
+			toDateEpoch:   day(3) + 3600,
+			built:         []int64{day(2), day(3)},
+			days:          []int64{},
+			live:          [][]int64{{day(2), day(3) + 3600}},
+		},
+	}
+
+	for _, test := range tests {
+		days, live := splitSnapshotWindow(test.fromDateEpoch, test.toDateEpoch, test.built)
+		assert.Equal(test.days, days, test.name)
+		assert.Equal(test.live, live, test.name)
+	}
+}
+
+func TestSnapshotDriverQueriesLive(t *testing.T) {
+	assert := assert.New(t)
+	driver := &countingDriver{dummyDriver: &dummyDriver{}}
+	// Without a connection, any query of the snapshots would fail
+	snapshots := NewSnapshotDriver(driver, nil, 30*24*time.Hour)
+	toDateEpoch := time.Date(2017, 5, 8, 0, 0, 0, 0, time.UTC).Unix()
+	filter := MentionFilter{Weighting: "count", Predicates: []string{"mentions"}}
+
+	things, found, err := snapshots.MostMentioned(context.Background(), "Person", toDateEpoch-7*secondsPerDay, toDateEpoch, PageRequest{Limit: 10}, filter)
+	assert.NoError(err)
+	assert.True(found)
+	assert.Len(things, 1)
+	assert.Equal(1, driver.callCount(), "Short windows should be queried live")
+
+	longFilters := []MentionFilter{
+		{Weighting: "count", Predicates: []string{"about"}},
+		{Weighting: "count", Predicates: []string{"mentions", "about"}},
+		{Weighting: "count", Predicates: []string{"mentions"}, MinConfidence: 0.5},
+		{Weighting: "count", Predicates: []string{"mentions"}, Brands: []string{"dbb0bdae-1f0c-11e4-b0cb-b2227cce2b54"}},
+		{Weighting: "count", Predicates: []string{"mentions"}, ContentTypes: []string{"Article"}},
+	}
+	for i, filter := range longFilters {
+		_, _, err := snapshots.MostMentioned(context.Background(), "Person", toDateEpoch-90*secondsPerDay, toDateEpoch, PageRequest{Limit: 10}, filter)
+		assert.NoError(err)
+		assert.Equal(i+2, driver.callCount(), "Filters not summarised by snapshots should be queried live")
+	}
+
+	disabled := NewSnapshotDriver(driver, nil, 0)
+	_, _, err = disabled.MostMentioned(context.Background(), "Person", toDateEpoch-90*secondsPerDay, toDateEpoch, PageRequest{Limit: 10}, MentionFilter{Weighting: "count", Predicates: []string{"mentions"}})
+	assert.NoError(err)
+	assert.Equal(len(longFilters)+2, driver.callCount(), "Snapshots should not be used without a minimum window")
+}
@@ -0,0 +1,114 @@
+package sixdegrees
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	logger "github.com/Financial-Times/go-logger"
+)
+
+// The endpoints whose queries can be given their own timeouts, by name
+var queryTimeoutEndpoints = []string{
+	"connectedPeople",
+	"connectedPeopleBatch",
+	"connectedConcepts",
+	"mostMentioned",
+	"trendingPeople",
+	"peopleSearch",
+	"mentions",
+	"connection",
+	"path",
+	"network",
+	"communities",
+	"centralPeople",
+}
+
+// QueryTimeouts bounds how long the queries of every endpoint may run, falling back to a default for endpoints without
+// their own. Zero durations leave queries bounded by their requests only
+type QueryTimeouts struct {
+	Default   time.Duration
+	Endpoints map[string]time.Duration
+}
+
+// ParseQueryTimeouts reads a default timeout, like 30s, and a comma separated list of the timeouts of endpoints, like
+// network=1m,path=45s
+func ParseQueryTimeouts(defaultTimeout string, endpointTimeouts string) (QueryTimeouts, error) {
+	timeouts := QueryTimeouts{Endpoints: map[string]time.Duration{}}
+	var err error
+	if timeouts.Default, err = time.ParseDuration(defaultTimeout); err != nil {
+		return timeouts, err
+	}
+
+	for _, endpointTimeout := range splitListParam(endpointTimeouts) {
+		parts := strings.SplitN(endpointTimeout, "=", 2)
+		if len(parts) != 2 {
+			return timeouts, fmt.Errorf("malformed timeout %s, must be endpoint=duration", endpointTimeout)
+		}
+		if !isQueryTimeoutEndpoint(parts[0]) {
+			return timeouts, fmt.Errorf("unknown endpoint %s, must be one of %s", parts[0], strings.Join(queryTimeoutEndpoints, ", "))
+		}
+		if timeouts.Endpoints[parts[0]], err = time.ParseDuration(parts[1]); err != nil {
+			return timeouts, err
+		}
+	}
+	return timeouts, nil
+}
+
+func isQueryTimeoutEndpoint(endpoint string) bool {
+	for _, known := range queryTimeoutEndpoints {
+		if endpoint == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (qt QueryTimeouts) of(endpoint string) time.Duration {
+	if timeout, found := qt.Endpoints[endpoint]; found {
+		return timeout
+	}
+	return qt.Default
+}
+
+// Longest is the most time any query may take, which clients of Neo4j without their own deadlines should allow for
+func (qt QueryTimeouts) Longest() time.Duration {
+	longest := qt.Default
+	for _, timeout := range qt.Endpoints {
+		if timeout > longest {
+			longest = timeout
+		}
+	}
+	return longest
+}
+
+// queryContext is done when the client goes away, or when the queries of the endpoint run out of time. Stale results
+// answering its queries are told of in the headers of the response
+func (hh *Handler) queryContext(w http.ResponseWriter, request *http.Request, endpoint string) (context.Context, context.CancelFunc) {
+	ctx := withStaleHeaders(request.Context(), w)
+	timeout := hh.timeouts.of(endpoint)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// writeTimeout answers 504 for queries that ran out of time, and nothing to clients gone away, telling whether the
+// error was either of them
+func writeTimeout(w http.ResponseWriter, err error) bool {
+	switch err {
+	case context.DeadlineExceeded:
+		logger.WithError(err).Warn("query timed out")
+		w.WriteHeader(http.StatusGatewayTimeout)
+		msg, _ := json.Marshal(ErrorMessage{"Timed out retrieving result from DB"})
+		w.Write([]byte(msg))
+		return true
+	case context.Canceled:
+		logger.WithError(err).Info("client went away before its query finished")
+		return true
+	}
+	return false
+}
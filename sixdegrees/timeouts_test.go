@@ -0,0 +1,82 @@
+package sixdegrees
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQueryTimeouts(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		name             string
+		defaultTimeout   string
+		endpointTimeouts string
+		expected         QueryTimeouts
+		err              string
+	}{
+		{
+			name:           "DefaultOnly",
+			defaultTimeout: "30s",
+			expected:       QueryTimeouts{Default: 30 * time.Second, Endpoints: map[string]time.Duration{}},
+		},
+		{
+			name:             "Endpoints",
+			defaultTimeout:   "30s",
+			endpointTimeouts: "network=1m, path=45s",
+			expected:         QueryTimeouts{Default: 30 * time.Second, Endpoints: map[string]time.Duration{"network": time.Minute, "path": 45 * time.Second}},
+		},
+		{
+			name:           "MalformedDefault",
+			defaultTimeout: "FAIL",
+			err:            "invalid duration",
+		},
+		{
+			name:             "MalformedEndpoint",
+			defaultTimeout:   "30s",
+			endpointTimeouts: "network",
+			err:              "malformed timeout network, must be endpoint=duration",
+		},
+		{
+			name:             "UnknownEndpoint",
+			defaultTimeout:   "30s",
+			endpointTimeouts: "FAIL=1m",
+			err:              "unknown endpoint FAIL, must be one of connectedPeople, connectedPeopleBatch, connectedConcepts, mostMentioned, trendingPeople, peopleSearch, mentions, connection, path, network, communities, centralPeople",
+		},
+	}
+
+	for _, test := range tests {
+		timeouts, err := ParseQueryTimeouts(test.defaultTimeout, test.endpointTimeouts)
+		if test.err != "" {
+			assert.Error(err, test.name)
+			assert.Contains(err.Error(), test.err, test.name)
+			continue
+		}
+		assert.NoError(err, test.name)
+		assert.Equal(test.expected, timeouts, test.name)
+	}
+}
+
+func TestQueryContext(t *testing.T) {
+	assert := assert.New(t)
+	timeouts := QueryTimeouts{Default: time.Minute, Endpoints: map[string]time.Duration{"network": time.Hour, "path": 0}}
+	handler := NewHandler(&dummyDriver{}, "max-age=360, public", testCursorSecret, timeouts)
+	request, _ := http.NewRequest("GET", "/sixdegrees/network", nil)
+
+	for endpoint, expected := range map[string]time.Duration{"network": time.Hour, "connectedPeople": time.Minute} {
+		ctx, cancel := handler.queryContext(httptest.NewRecorder(), request, endpoint)
+		deadline, ok := ctx.Deadline()
+		cancel()
+		assert.True(ok, endpoint)
+		assert.InDelta(expected.Seconds(), time.Until(deadline).Seconds(), 5, endpoint)
+	}
+
+	ctx, cancel := handler.queryContext(httptest.NewRecorder(), request, "path")
+	defer cancel()
+	_, ok := ctx.Deadline()
+	assert.False(ok, "Endpoints with a zero timeout should have no deadline")
+	assert.Equal(time.Hour, timeouts.Longest())
+}